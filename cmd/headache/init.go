@@ -0,0 +1,138 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	. "github.com/fbiville/headache/internal/pkg/core"
+	"github.com/fbiville/headache/internal/pkg/fs"
+	"github.com/fbiville/headache/internal/pkg/license"
+)
+
+func initialize(args []string) {
+	flags := flag.NewFlagSet("headache init", flag.ExitOnError)
+	configFile := configurationFlag(flags)
+	headerFile := flags.String("header-file", "license-header.txt", "Path to the header template to generate")
+	licenseId := flags.String("license", "Apache-2.0", fmt.Sprintf("SPDX identifier of the license, one of: %s", strings.Join(license.SupportedIds(), ", ")))
+	owner := flags.String("owner", "The original author or authors", "Copyright owner")
+	style := flags.String("style", "", "Default comment style, detected if not set")
+	extensionStyles := flags.String("extension-styles", "", "Comma-separated comment styles by file extension (e.g. py=Hash,sql=DashDash), detected if not set")
+	includes := flags.String("includes", "", "Comma-separated file globs to include, detected if not set")
+	excludes := flags.String("excludes", "", "Comma-separated file globs to exclude, detected if not set")
+	nonInteractive := flags.Bool("non-interactive", false, "Rely on flags and detected values only, without prompting")
+	force := flags.Bool("force", false, "Overwrite existing configuration and header template")
+	_ = flags.Parse(args)
+	explicitFlags := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { explicitFlags[f.Name] = true })
+
+	fileSystem := fs.DefaultFileSystem()
+	for _, path := range []string{*configFile, *headerFile} {
+		if !*force && fileSystem.IsFile(path) {
+			log.Fatalf("headache init error, %s already exists, use --force to overwrite it", path)
+		}
+	}
+
+	prompt := &prompter{interactive: !*nonInteractive, explicitFlags: explicitFlags, reader: bufio.NewReader(os.Stdin)}
+	*licenseId = prompt.ask("license", "License SPDX identifier", *licenseId)
+	*owner = prompt.ask("owner", "Copyright owner", *owner)
+
+	initializer := &ProjectInitializer{FileSystem: fileSystem, PathMatcher: &fs.ZglobPathMatcher{}}
+	configuration, err := initializer.Propose(*headerFile, *owner)
+	if err != nil {
+		log.Fatalf("headache init error, cannot detect project languages\n\t%v", err)
+	}
+	configuration.CommentStyle = ParseCommentStyle(prompt.ask("style", "Default comment style", orDefault(*style, configuration.CommentStyle))).GetName()
+	configuration.ExtensionStyles = parseExtensionStyles(prompt.ask("extension-styles", "Comment styles by file extension", orDefault(*extensionStyles, formatExtensionStyles(configuration.ExtensionStyles))))
+	configuration.Includes = splitList(prompt.ask("includes", "Included files", orDefault(*includes, strings.Join(configuration.Includes, ","))))
+	configuration.Excludes = splitList(prompt.ask("excludes", "Excluded files", orDefault(*excludes, strings.Join(configuration.Excludes, ","))))
+
+	if err := initializer.Write(*configFile, configuration, *licenseId); err != nil {
+		log.Fatalf("headache init error, cannot write files\n\t%v", err)
+	}
+	log.Printf("Generated %s and %s", *configFile, *headerFile)
+}
+
+type prompter struct {
+	interactive   bool
+	explicitFlags map[string]bool
+	reader        *bufio.Reader
+}
+
+// prompts for the value of a setting, unless prompts are disabled or the setting is explicitly set by flag
+func (p *prompter) ask(flagName string, question string, defaultValue string) string {
+	if !p.interactive || p.explicitFlags[flagName] {
+		return defaultValue
+	}
+	fmt.Printf("%s [%s]: ", question, defaultValue)
+	answer, err := p.reader.ReadString('\n')
+	if err != nil && answer == "" {
+		return defaultValue
+	}
+	if answer = strings.TrimSpace(answer); answer != "" {
+		return answer
+	}
+	return defaultValue
+}
+
+func orDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func splitList(value string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func parseExtensionStyles(value string) map[string]string {
+	items := splitList(value)
+	if len(items) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(items))
+	for _, item := range items {
+		extensionStyle := strings.SplitN(item, "=", 2)
+		if len(extensionStyle) != 2 {
+			log.Fatalf("headache usage error, expected extension=style pair, got %q", item)
+		}
+		result[strings.TrimSpace(extensionStyle[0])] = ParseCommentStyle(strings.TrimSpace(extensionStyle[1])).GetName()
+	}
+	return result
+}
+
+func formatExtensionStyles(extensionStyles map[string]string) string {
+	result := make([]string, 0, len(extensionStyles))
+	for extension, style := range extensionStyles {
+		result = append(result, fmt.Sprintf("%s=%s", extension, style))
+	}
+	sort.Strings(result)
+	return strings.Join(result, ",")
+}
@@ -18,15 +18,91 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
 	. "github.com/fbiville/headache/internal/pkg/core"
 	"github.com/fbiville/headache/internal/pkg/fs"
-	"log"
+	"github.com/fbiville/headache/internal/pkg/report"
 )
 
+type dependencies struct {
+	configLoader          *ConfigurationFileLoader
+	executionTracker      *ExecutionVcsTracker
+	configurationResolver *ConfigurationResolver
+	headache              *Headache
+}
+
 func main() {
+	command, args := "", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+	switch command {
+	case "":
+		update(args)
+	case "init":
+		initialize(args)
+	case "remove":
+		remove(args)
+	case "reuse":
+		reuse(args)
+	default:
+		log.Fatalf("headache usage error, unexpected command %q\n\tmust be one of: init,remove,reuse", command)
+	}
+}
+
+func update(args []string) {
+	flags := flag.NewFlagSet("headache", flag.ExitOnError)
+	configFile := configurationFlag(flags)
+	check := flags.Bool("check", false, "Report files with missing or outdated headers instead of updating them")
+	repair := flags.Bool("repair", false, "Collapse stacked copies of the header into one")
+	verbose := flags.Bool("verbose", false, "Log the confidence score of the headers found by fuzzy detection")
+	format := formatFlag(flags)
+	_ = flags.Parse(args)
+	reporter := parseReporter(*format)
+
 	log.Print("Starting...")
 
-	// dependency graph - begin
+	deps := newDependencies()
+	configuration := loadConfiguration(deps, *configFile)
+	resolve := deps.configurationResolver.ResolveEagerly
+	if *check {
+		// already non-compliant files must be reported as well, not only the ones changed since the last execution
+		resolve = deps.configurationResolver.ResolveFully
+	}
+	changeSet, err := resolve(configuration)
+	if err != nil {
+		log.Fatalf("headache configuration error, cannot parse\n\t%v\n", err)
+	}
+	changeSet.Repair = *repair
+	if *check {
+		reports := deps.headache.Check(changeSet)
+		logConfidences(*verbose, reports)
+		writeReport(reporter, reports)
+		if violations := countChanges(reports); violations > 0 {
+			log.Fatalf("headache check failed, %d file(s) with missing or outdated header", violations)
+		}
+	} else if len(changeSet.Files) > 0 || len(changeSet.SidecarFiles) > 0 || len(changeSet.OrphanedSidecars) > 0 {
+		reports := deps.headache.Run(changeSet)
+		logConfidences(*verbose, reports)
+		// regular runs fix the files, their report is only written on demand
+		if isPassed(flags, "format") {
+			writeReport(reporter, reports)
+		}
+		if err := deps.executionTracker.TrackExecution(configFile); err != nil {
+			log.Printf("headache warning, could not save current execution, see below for details\n\t%v\n", err)
+		}
+	} else {
+		log.Print("No files to process")
+	}
+
+	log.Print("Done!")
+}
+
+func newDependencies() *dependencies {
 	environment := DefaultEnvironment()
 	fileSystem := environment.FileSystem
 	configLoader := &ConfigurationFileLoader{
@@ -40,38 +116,76 @@ func main() {
 		Clock:        environment.Clock,
 		ConfigLoader: configLoader,
 	}
-	configurationResolver := &ConfigurationResolver{
-		Environment:      environment,
-		ExecutionTracker: executionTracker,
-		PathMatcher:      &fs.ZglobPathMatcher{},
+	return &dependencies{
+		configLoader:     configLoader,
+		executionTracker: executionTracker,
+		configurationResolver: &ConfigurationResolver{
+			Environment:      environment,
+			ExecutionTracker: executionTracker,
+			PathMatcher:      &fs.ZglobPathMatcher{},
+		},
+		headache: &Headache{Fs: fileSystem, Clock: environment.Clock},
 	}
-	headache := &Headache{Fs: fileSystem}
-	// dependency graph - end
+}
 
-	configFile, configuration := loadConfiguration(configLoader, configurationResolver)
-	if len(configuration.Files) > 0 {
-		headache.Run(configuration)
-		if err := executionTracker.TrackExecution(configFile); err != nil {
-			log.Printf("headache warning, could not save current execution, see below for details\n\t%v\n", err)
-		}
-	} else {
-		log.Print("No files to process")
-	}
+func configurationFlag(flags *flag.FlagSet) *string {
+	return flags.String("configuration", "headache.json", "Path to configuration file")
+}
 
-	log.Print("Done!")
+func formatFlag(flags *flag.FlagSet) *string {
+	return flags.String("format", "text", fmt.Sprintf("Format of the execution report, one of: %s", strings.Join(report.SupportedFormats(), ", ")))
 }
 
-func loadConfiguration(configLoader *ConfigurationFileLoader, configResolver *ConfigurationResolver) (*string, *ChangeSet) {
-	configFile := flag.String("configuration", "headache.json", "Path to configuration file")
-	flag.Parse()
+// checks whether the flag has been explicitly set on the command line
+func isPassed(flags *flag.FlagSet, name string) bool {
+	result := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			result = true
+		}
+	})
+	return result
+}
 
-	userConfiguration, err := configLoader.ValidateAndLoad(*configFile)
+func parseReporter(format string) report.Reporter {
+	reporter, err := report.ParseReporter(format)
 	if err != nil {
-		log.Fatalf("headache configuration error, cannot load\n\t%v\n", err)
+		log.Fatalf("headache usage error, %v", err)
 	}
-	configuration, err := configResolver.ResolveEagerly(userConfiguration)
+	return reporter
+}
+
+func loadConfiguration(deps *dependencies, configFile string) *Configuration {
+	configuration, err := deps.configLoader.ValidateAndLoad(configFile)
 	if err != nil {
-		log.Fatalf("headache configuration error, cannot parse\n\t%v\n", err)
+		log.Fatalf("headache configuration error, cannot load\n\t%v\n", err)
+	}
+	return configuration
+}
+
+func writeReport(reporter report.Reporter, reports []FileReport) {
+	if err := reporter.Report(os.Stdout, reports); err != nil {
+		log.Fatalf("headache execution error, cannot write report\n\t%v\n", err)
+	}
+}
+
+func countChanges(reports []FileReport) int {
+	result := 0
+	for _, fileReport := range reports {
+		if fileReport.RequiresChange() {
+			result++
+		}
+	}
+	return result
+}
+
+func logConfidences(verbose bool, reports []FileReport) {
+	if !verbose {
+		return
+	}
+	for _, fileReport := range reports {
+		if fileReport.Confidence > 0 {
+			log.Printf("%s: header detected with a confidence of %.2f", fileReport.Path, fileReport.Confidence)
+		}
 	}
-	return configFile, configuration
 }
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"log"
+)
+
+func remove(args []string) {
+	flags := flag.NewFlagSet("headache remove", flag.ExitOnError)
+	configFile := configurationFlag(flags)
+	headerFile := flags.String("header-file", "", "Path to the header template to remove, defaults to the configured one")
+	dryRun := flags.Bool("dry-run", false, "Report files with a header to remove instead of removing it")
+	format := formatFlag(flags)
+	_ = flags.Parse(args)
+	reporter := parseReporter(*format)
+
+	log.Print("Starting...")
+
+	deps := newDependencies()
+	configuration := loadConfiguration(deps, *configFile)
+	if *headerFile != "" {
+		configuration.HeaderFile = *headerFile
+		configuration.License = ""
+		configuration.HeaderMode = ""
+	}
+	changeSet, err := deps.configurationResolver.ResolveAll(configuration)
+	if err != nil {
+		log.Fatalf("headache configuration error, cannot parse\n\t%v\n", err)
+	}
+	writeReport(reporter, deps.headache.Remove(changeSet, *dryRun))
+
+	log.Print("Done!")
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"log"
+
+	. "github.com/fbiville/headache/internal/pkg/core"
+	"github.com/fbiville/headache/internal/pkg/fs"
+)
+
+func reuse(args []string) {
+	flags := flag.NewFlagSet("headache reuse", flag.ExitOnError)
+	configFile := configurationFlag(flags)
+	dep5 := flags.Bool("dep5", false, "Write the legacy .reuse/dep5 file instead of REUSE.toml")
+	output := flags.String("output", "", "Path to the REUSE file to write, defaults to REUSE.toml or .reuse/dep5")
+	_ = flags.Parse(args)
+
+	log.Print("Starting...")
+
+	deps := newDependencies()
+	configuration := loadConfiguration(deps, *configFile)
+	if len(configuration.Annotations) == 0 {
+		log.Fatalf("headache configuration error, no annotations configured in %s", *configFile)
+	}
+	format := ReuseToml
+	if *dep5 {
+		format = ReuseDep5
+	}
+	path := *output
+	if path == "" {
+		path = format.DefaultPath()
+	}
+
+	environment := deps.configurationResolver.Environment
+	annotator := &ReuseAnnotator{Environment: environment, PathMatcher: &fs.ZglobPathMatcher{}}
+	annotation, err := annotator.Annotate(configuration, format)
+	if err != nil {
+		log.Fatalf("headache execution error, cannot compute REUSE annotation\n\t%v\n", err)
+	}
+	changed, err := annotator.Write(path, annotation, format)
+	if err != nil {
+		log.Fatalf("headache execution error, cannot write %s\n\t%v\n", path, err)
+	}
+	if changed {
+		log.Printf("Updated %s", path)
+	} else {
+		log.Printf("%s is up-to-date", path)
+	}
+
+	log.Print("Done!")
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fbiville/headache/internal/pkg/vcs"
+)
+
+const (
+	// authors are sorted by their first commit
+	ChronologicalAuthorOrder = "chronological"
+	// authors are sorted by name
+	AlphabeticalAuthorOrder = "alphabetical"
+	// authors are sorted by decreasing number of commits
+	CommitCountAuthorOrder = "commits"
+)
+
+// Settings of the {{.Authors}} and {{.AuthorsWithYears}} reserved parameters
+type AuthorOptions struct {
+	Sort string `json:"sort,omitempty"`
+	// maximum number of listed authors, the other ones being summed up as "others"
+	Limit int `json:"limit,omitempty"`
+	// organization of the authors, keyed by email or by email domain (e.g. "@acme.com")
+	Organizations map[string]string `json:"organizations,omitempty"`
+}
+
+// copyright holder, either an author or the organization of authors
+type holder struct {
+	name    string
+	years   []int
+	commits int
+}
+
+// computes the {{.Authors}} and {{.AuthorsWithYears}} reserved parameters from the authors of the file
+func authorParameters(authors []vcs.Author, options *AuthorOptions) map[string]string {
+	if options == nil {
+		options = &AuthorOptions{}
+	}
+	holders := sortHolders(groupHolders(authors, options.Organizations), options.Sort)
+	truncated := options.Limit > 0 && len(holders) > options.Limit
+	if truncated {
+		holders = holders[:options.Limit]
+	}
+	names := make([]string, len(holders))
+	namesWithYears := make([]string, len(holders))
+	for i, holder := range holders {
+		names[i] = holder.name
+		namesWithYears[i] = fmt.Sprintf("%s (%s)", holder.name, yearList(holder.years))
+	}
+	return map[string]string{
+		"Authors":          joinHolders(names, truncated),
+		"AuthorsWithYears": joinHolders(namesWithYears, truncated),
+	}
+}
+
+func groupHolders(authors []vcs.Author, organizations map[string]string) []*holder {
+	result := make([]*holder, 0)
+	holdersByName := make(map[string]*holder)
+	for _, author := range authors {
+		name := holderName(author, organizations)
+		current, found := holdersByName[name]
+		if !found {
+			current = &holder{name: name}
+			holdersByName[name] = current
+			result = append(result, current)
+		}
+		current.commits += author.Commits
+		current.years = mergeYears(current.years, author.Years)
+	}
+	return result
+}
+
+func holderName(author vcs.Author, organizations map[string]string) string {
+	email := strings.ToLower(author.Email)
+	for key, organization := range organizations {
+		key = strings.ToLower(key)
+		if email == key || (strings.HasPrefix(key, "@") && strings.HasSuffix(email, key)) {
+			return organization
+		}
+	}
+	if author.Name == "" {
+		return author.Email
+	}
+	return author.Name
+}
+
+func sortHolders(holders []*holder, order string) []*holder {
+	switch order {
+	case AlphabeticalAuthorOrder:
+		sort.SliceStable(holders, func(i, j int) bool {
+			return strings.ToLower(holders[i].name) < strings.ToLower(holders[j].name)
+		})
+	case CommitCountAuthorOrder:
+		sort.SliceStable(holders, func(i, j int) bool {
+			return holders[i].commits > holders[j].commits
+		})
+	}
+	return holders
+}
+
+func joinHolders(names []string, truncated bool) string {
+	result := strings.Join(names, ", ")
+	if truncated {
+		result += " and others"
+	}
+	return result
+}
+
+func mergeYears(years []int, otherYears []int) []int {
+	result := append([]int{}, years...)
+	for _, year := range otherYears {
+		found := false
+		for _, existingYear := range result {
+			if existingYear == year {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, year)
+		}
+	}
+	sort.Ints(result)
+	return result
+}
@@ -19,6 +19,7 @@ package core
 import (
 	"log"
 	"strings"
+	"unicode/utf8"
 
 	styles "github.com/fbiville/headache/internal/pkg/core/comment_styles"
 )
@@ -30,14 +31,21 @@ type CommentStyle interface {
 	GetClosingString() string
 }
 
+// Comment style whose delimiters come in several variants, such as Lua's long comments
+// The variants are detected, but the plain opening and closing strings are the ones inserted
+type DelimiterVariants interface {
+	GetOpeningRegex() string
+	GetClosingRegex() string
+}
+
 func CommentStyleSorter(styles []CommentStyle) func(i, j int) bool {
 	return func(i, j int) bool {
 		return styles[i].GetName() < styles[j].GetName()
 	}
 }
 
-func SupportedStyleCatalog() map[string]CommentStyle {
-	commentStyles := SupportedStyles()
+func SupportedStyleCatalog(customStyles ...CommentStyle) map[string]CommentStyle {
+	commentStyles := SupportedStyles(customStyles...)
 	result := make(map[string]CommentStyle, len(commentStyles))
 	for _, style := range commentStyles {
 		result[style.GetName()] = style
@@ -45,7 +53,35 @@ func SupportedStyleCatalog() map[string]CommentStyle {
 	return result
 }
 
-func SupportedStyles() []CommentStyle {
+// Comment style declared in the configuration, along with the file extensions it applies to
+type CustomStyle struct {
+	Name       string   `json:"name"`
+	Opening    string   `json:"opening,omitempty"`
+	Prefix     string   `json:"prefix"`
+	Closing    string   `json:"closing,omitempty"`
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// Returns the comment styles of the custom style definitions
+func CustomCommentStyles(definitions []CustomStyle) []CommentStyle {
+	result := make([]CommentStyle, len(definitions))
+	for i, definition := range definitions {
+		result[i] = styles.Custom{
+			Name:    definition.Name,
+			Opening: definition.Opening,
+			Prefix:  definition.Prefix,
+			Closing: definition.Closing,
+		}
+	}
+	return result
+}
+
+// Returns the built-in comment styles, followed by the given custom styles
+func SupportedStyles(customStyles ...CommentStyle) []CommentStyle {
+	return append(BuiltInStyles(), customStyles...)
+}
+
+func BuiltInStyles() []CommentStyle {
 	return []CommentStyle{
 		styles.SlashStar{},
 		styles.SlashSlash{},
@@ -56,11 +92,22 @@ func SupportedStyles() []CommentStyle {
 		styles.SlashStarStar{},
 		styles.Xml{},
 		styles.SingleQuote{},
+		styles.ParenStar{},
+		styles.BraceDash{},
+		styles.Percent{},
+		styles.PercentPercent{},
+		styles.Exclamation{},
+		styles.Jinja{},
+		styles.Handlebars{},
+		styles.Jsp{},
+		styles.Lua{},
+		styles.Docstring{},
 	}
 }
 
-func ParseCommentStyle(name string) CommentStyle {
-	commentStyles := SupportedStyleCatalog()
+// Returns the built-in or custom comment style of the given name, the lookup being case-insensitive
+func ParseCommentStyle(name string, customStyles ...CommentStyle) CommentStyle {
+	commentStyles := SupportedStyleCatalog(customStyles...)
 	for styleName, style := range commentStyles {
 		if strings.ToLower(styleName) == strings.ToLower(name) {
 			return style
@@ -85,7 +132,45 @@ func ApplyComments(lines []string, style CommentStyle) ([]string, error) {
 	return result, nil
 }
 
+// Breaks the commented lines of the header that are longer than maxLineLength, continuation lines keep the comment
+// prefix and the indentation of the original line
+func WrapComments(header string, style CommentStyle, maxLineLength int) string {
+	prefix := style.GetString()
+	lines := strings.Split(header, "\n")
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) <= maxLineLength || prefix == "" || !strings.HasPrefix(line, prefix) {
+			result = append(result, line)
+			continue
+		}
+		content := strings.TrimPrefix(line, prefix)
+		indentation := content[:len(content)-len(strings.TrimLeft(content, " \t"))]
+		width := maxLineLength - utf8.RuneCountInString(prefix+indentation)
+		for _, wrappedLine := range strings.Split(wrap(width, content), "\n") {
+			result = append(result, prefix+indentation+wrappedLine)
+		}
+	}
+	return strings.Join(result, "\n")
+}
+
+// Returns the comment style with its delimiters escaped from the template actions delimiters, such as Handlebars' {{!--
+func escapeActionDelimiters(style CommentStyle) CommentStyle {
+	escape := func(str string) string {
+		return strings.Replace(str, "{{", `{{"{{"}}`, -1)
+	}
+	return styles.Custom{
+		Name:    style.GetName(),
+		Opening: escape(style.GetOpeningString()),
+		Prefix:  escape(style.GetString()),
+		Closing: escape(style.GetClosingString()),
+	}
+}
+
 func prependLine(style CommentStyle, line string) string {
+	// conditional sections evaluated file by file are not part of the comment
+	if isControlLine(line) {
+		return line
+	}
 	comment := style.GetString()
 	if line == "" {
 		return strings.TrimRight(comment, " ")
@@ -48,14 +48,34 @@ var _ = Describe("Comment styles", func() {
 		Entry("matches SlashStarStar comment style", "SlashStarStar", "/**", " */", " * "),
 		Entry("matches XML comment style", "XML", "<!--", "-->", ""),
 		Entry("matches SingleQuote comment style", "SingleQuote", "", "", "' "),
+		Entry("matches ParenStar comment style", "ParenStar", "(*", " *)", " * "),
+		Entry("matches BraceDash comment style", "BraceDash", "{-", " -}", " - "),
+		Entry("matches Percent comment style", "Percent", "", "", "% "),
+		Entry("matches PercentPercent comment style", "PercentPercent", "", "", "%% "),
+		Entry("matches Exclamation comment style", "Exclamation", "", "", "! "),
+		Entry("matches Jinja comment style", "Jinja", "{#", "#}", ""),
+		Entry("matches Handlebars comment style", "Handlebars", "{{!--", "--}}", ""),
+		Entry("matches JSP comment style", "JSP", "<%--", "--%>", ""),
+		Entry("matches Lua comment style", "Lua", "--[[", "]]", ""),
+		Entry("matches Docstring comment style", "Docstring", `"""`, `"""`, ""),
 	)
 
 	It("include only the following", func() {
 		catalog := namesOf(SupportedStyleCatalog())
 
 		Expect(catalog).To(Equal([]string{
+			"BraceDash",
 			"DashDash",
+			"Docstring",
+			"Exclamation",
+			"Handlebars",
 			"Hash",
+			"JSP",
+			"Jinja",
+			"Lua",
+			"ParenStar",
+			"Percent",
+			"PercentPercent",
 			"REM",
 			"SemiColon",
 			"SingleQuote",
@@ -73,6 +93,46 @@ var _ = Describe("Comment styles", func() {
 
 		Expect(style.GetName()).To(Equal("SlashSlash"))
 	})
+
+	It("include the given custom styles", func() {
+		definitions := []CustomStyle{{Name: "Pascal", Opening: "(*", Prefix: " * ", Closing: " *)"}}
+
+		lines, err := ApplyComments([]string{"Copyright ACME"}, ParseCommentStyle("pascal", CustomCommentStyles(definitions)...))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lines).To(Equal([]string{"(*", " * Copyright ACME", " *)"}))
+		template := &HeaderTemplate{Lines: []string{"Copyright ACME"}, Data: map[string]string{}, CustomStyles: definitions}
+		result, err := ParseTemplate(&VersionedHeaderTemplate{Current: template, Previous: template}, ParseCommentStyle("hash"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.DetectionRegex.FindString("(*\n * Copyright ACME\n *)\nprogram Foo;")).To(Equal("(*\n * Copyright ACME\n *)"))
+	})
+
+	It("detect the headers of custom styles removed from the configuration since the previous execution", func() {
+		previousTemplate := &HeaderTemplate{
+			Lines:        []string{"Copyright ACME"},
+			Data:         map[string]string{},
+			CustomStyles: []CustomStyle{{Name: "Pascal", Opening: "(*", Prefix: " * ", Closing: " *)"}},
+		}
+		currentTemplate := &HeaderTemplate{Lines: []string{"Copyright ACME"}, Data: map[string]string{}}
+
+		result, err := ParseTemplate(&VersionedHeaderTemplate{Current: currentTemplate, Previous: previousTemplate}, ParseCommentStyle("hash"))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.DetectionRegex.FindString("(*\n * Copyright ACME\n *)\nprogram Foo;")).To(Equal("(*\n * Copyright ACME\n *)"))
+	})
+
+	It("wrap long commented lines with the comment prefix and indentation", func() {
+		header := "/*\n * Copyright 2020 Jane Doe, John Smith and many others\n *\n *    https://example.com/a/rather/long/url\n */"
+
+		result := WrapComments(header, ParseCommentStyle("SlashStar"), 30)
+
+		Expect(result).To(Equal("/*\n" +
+			" * Copyright 2020 Jane Doe,\n" +
+			" * John Smith and many others\n" +
+			" *\n" +
+			" *    https://example.com/a/rather/long/url\n" +
+			" */"))
+	})
 })
 
 func namesOf(styles map[string]CommentStyle) []string {
@@ -104,18 +164,24 @@ type HeadacheProperties struct {
 }
 
 type CommentStyleProperty struct {
+	AnyOf []CommentStyleAlternative `json:"anyOf"`
+}
+
+type CommentStyleAlternative struct {
 	Names []string `json:"enum"`
 }
 
 func (schema *HeadacheSchema) SortedStyleNames() []string {
-	result := schema.Properties.Style.Names
+	result := schema.Properties.Style.AnyOf[0].Names
 	sort.Strings(result)
 	return result
 }
 
 func lowerAll(catalog []string) []string {
+	result := make([]string, len(catalog))
 	for i, value := range catalog {
-		catalog[i] = strings.ToLower(value)
+		result[i] = strings.ToLower(value)
 	}
-	return catalog
+	sort.Strings(result)
+	return result
 }
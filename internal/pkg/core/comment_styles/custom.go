@@ -0,0 +1,38 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package styles
+
+// Comment style declared in the configuration
+type Custom struct {
+	Name    string
+	Opening string
+	Prefix  string
+	Closing string
+}
+
+func (style Custom) GetName() string {
+	return style.Name
+}
+func (style Custom) GetOpeningString() string {
+	return style.Opening
+}
+func (style Custom) GetString() string {
+	return style.Prefix
+}
+func (style Custom) GetClosingString() string {
+	return style.Closing
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package styles
+
+type Lua struct{}
+
+func (Lua) GetName() string {
+	return "Lua"
+}
+func (Lua) GetOpeningString() string {
+	return "--[["
+}
+func (Lua) GetString() string {
+	return ""
+}
+func (Lua) GetClosingString() string {
+	return "]]"
+}
+
+// long comments may be of any level, e.g. --[==[ ... ]==]
+func (Lua) GetOpeningRegex() string {
+	return `--\[=*\[`
+}
+func (Lua) GetClosingRegex() string {
+	return `\]=*\]`
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/fbiville/headache/internal/pkg/fs"
+	"github.com/fbiville/headache/internal/pkg/license"
 	json_schema "github.com/xeipuuv/gojsonschema"
 	"strings"
 )
@@ -42,25 +43,141 @@ func (loader *ConfigurationFileLoader) ValidateAndLoad(path string) (*Configurat
 	if err != nil {
 		return nil, err
 	}
-	if schema == nil {
-		return configuration, nil
-	}
 	// normalize comment style names
 	configuration.CommentStyle = strings.ToLower(configuration.CommentStyle)
+	for extension, style := range configuration.ExtensionStyles {
+		configuration.ExtensionStyles[extension] = strings.ToLower(style)
+	}
+	configuration.StylePreambles = lowerKeys(configuration.StylePreambles)
+	configuration.StyleMaxLineLengths = lowerLengthKeys(configuration.StyleMaxLineLengths)
+	// normalize SPDX license identifiers, which are case-insensitive
+	configuration.License = license.CanonicalId(configuration.License)
+	configuration.HeaderMode = strings.ToLower(configuration.HeaderMode)
+	configuration.ForeignHeaders = strings.ToLower(configuration.ForeignHeaders)
+	if configuration.Authors != nil {
+		configuration.Authors.Sort = strings.ToLower(configuration.Authors.Sort)
+	}
+	// the schema may be unavailable, custom styles are resolved regardless
+	if schema != nil {
+		if err := validate(schema, configuration); err != nil {
+			return nil, err
+		}
+	}
+	if err := resolveCustomStyles(configuration); err != nil {
+		return nil, err
+	}
+	configuration.Path = &path
+	return configuration, nil
+}
+
+func validate(schema *json_schema.Schema, configuration *Configuration) error {
 	configurationPayload, err := json.Marshal(configuration)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	result, err := schema.Validate(json_schema.NewBytesLoader(configurationPayload))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	errors := result.Errors()
 	if len(errors) > 0 {
-		return nil, fmt.Errorf(report(errors))
+		return fmt.Errorf(report(errors))
 	}
-	configuration.Path = &path
-	return configuration, nil
+	return nil
+}
+
+// checks that the configured styles are either built-in or custom ones, and assigns the custom styles to their
+// file extensions
+// the schema only enumerates the built-in styles
+func resolveCustomStyles(configuration *Configuration) error {
+	builtInNames := namesOf(BuiltInStyles())
+	knownNames := append([]string{}, builtInNames...)
+	for i, customStyle := range configuration.CustomStyles {
+		name := strings.ToLower(customStyle.Name)
+		if containsName(knownNames, name) {
+			return fmt.Errorf("Error with field 'customStyles.%d.name': %s is already defined", i, customStyle.Name)
+		}
+		knownNames = append(knownNames, name)
+	}
+	if !containsName(knownNames, configuration.CommentStyle) {
+		return unknownStyle("style", knownNames)
+	}
+	for _, style := range configuration.ExtensionStyles {
+		if !containsName(knownNames, style) {
+			return unknownStyle("extensionStyles", knownNames)
+		}
+	}
+	for style := range configuration.StylePreambles {
+		if !containsName(knownNames, style) {
+			return unknownStyle("stylePreambles", knownNames)
+		}
+	}
+	for style := range configuration.StyleMaxLineLengths {
+		if !containsName(knownNames, style) {
+			return unknownStyle("styleMaxLineLengths", knownNames)
+		}
+	}
+	for _, customStyle := range configuration.CustomStyles {
+		for _, extension := range customStyle.Extensions {
+			extension = strings.TrimPrefix(extension, ".")
+			// explicit extension styles take precedence
+			if _, found := configuration.ExtensionStyles[extension]; found {
+				continue
+			}
+			if configuration.ExtensionStyles == nil {
+				configuration.ExtensionStyles = make(map[string]string)
+			}
+			configuration.ExtensionStyles[extension] = strings.ToLower(customStyle.Name)
+		}
+	}
+	return nil
+}
+
+func lowerKeys(preambles map[string][]string) map[string][]string {
+	if preambles == nil {
+		return nil
+	}
+	result := make(map[string][]string, len(preambles))
+	for style, regexes := range preambles {
+		result[strings.ToLower(style)] = regexes
+	}
+	return result
+}
+
+func lowerLengthKeys(maxLineLengths map[string]int) map[string]int {
+	if maxLineLengths == nil {
+		return nil
+	}
+	result := make(map[string]int, len(maxLineLengths))
+	for style, maxLineLength := range maxLineLengths {
+		result[strings.ToLower(style)] = maxLineLength
+	}
+	return result
+}
+
+func namesOf(styles []CommentStyle) []string {
+	result := make([]string, len(styles))
+	for i, style := range styles {
+		result[i] = strings.ToLower(style.GetName())
+	}
+	return result
+}
+
+func containsName(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
+
+func unknownStyle(field string, names []string) error {
+	quotedNames := make([]string, len(names))
+	for i, name := range names {
+		quotedNames[i] = fmt.Sprintf("%q", name)
+	}
+	return fmt.Errorf("Error with field '%s': %s must be one of the following: %s", field, field, strings.Join(quotedNames, ", "))
 }
 
 func (loader *ConfigurationFileLoader) LoadFile(path string) (*Configuration, error) {
@@ -83,6 +200,10 @@ func (loader *ConfigurationFileLoader) LoadBytes(configurationPayload []byte) (*
 func report(errors []json_schema.ResultError) string {
 	builder := strings.Builder{}
 	for _, validationError := range errors {
+		// the errors of the conditional schema are reported on their own fields already
+		if validationError.Type() == "condition_else" {
+			continue
+		}
 		details := validationError.Details()
 		field := details["field"]
 		builder.WriteString(fmt.Sprintf("Error with field '%s': %s", field, description(field, validationError)))
@@ -93,10 +214,19 @@ func report(errors []json_schema.ResultError) string {
 }
 
 func description(field interface{}, validationError json_schema.ResultError) string {
-	for _, name := range []string{"Year", "YearRange", "StartYear", "EndYear"} {
+	for _, name := range reservedParameterNames() {
 		if field == fmt.Sprintf("data.%s", name) {
 			return fmt.Sprintf("%s is a reserved data parameter and cannot be used", name)
 		}
 	}
+	if field == "(root)" && validationError.Type() == "number_one_of" {
+		return "either headerFile or license must be set, but not both"
+	}
+	if field == "(root)" && validationError.Type() == "missing_dependency" {
+		return fmt.Sprintf("%s must be set to use sidecars or annotations", validationError.Details()["dependency"])
+	}
+	if field == "(root)" && validationError.Type() == "condition_then" {
+		return "the spdx header mode requires license to be set"
+	}
 	return validationError.Description()
 }
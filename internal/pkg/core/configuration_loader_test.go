@@ -170,14 +170,219 @@ var _ = Describe("Configuration loader", func() {
 		}))
 	})
 
-	It("rejects configuration with missing header file", func() {
+	It("accepts and loads comment styles by file extension in a case-insensitive way", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-file.txt", "style": "slashstar", "extensionStyles": {"py": "Hash", ".sql": "dashdash"}, "includes": ["**/*.go"]}`), nil)
+
+		configuration, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError).To(BeNil())
+		Expect(configuration).To(Equal(&core.Configuration{
+			HeaderFile:      "some-file.txt",
+			CommentStyle:    "slashstar",
+			ExtensionStyles: map[string]string{"py": "hash", ".sql": "dashdash"},
+			Includes:        []string{"**/*.go"},
+			Path:            &configurationUri,
+		}))
+	})
+
+	It("accepts and loads custom comment styles", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-file.txt", "style": "Fortran", "extensionStyles": {"f": "hash"},
+				"customStyles": [{"name": "Fortran", "prefix": "! ", "extensions": ["f90", ".f"]}], "includes": ["**/*.f90"]}`), nil)
+
+		configuration, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError).To(BeNil())
+		Expect(configuration.CommentStyle).To(Equal("fortran"))
+		Expect(configuration.ExtensionStyles).To(Equal(map[string]string{"f": "hash", "f90": "fortran"}))
+		Expect(core.ParseCommentStyle("fortran", core.CustomCommentStyles(configuration.CustomStyles)...).GetString()).To(Equal("! "))
+	})
+
+	It("resolves custom comment styles when the schema cannot be loaded", func() {
+		loader.SchemaLoader = &UnavailableSchemaLoader{}
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-file.txt", "style": "Fortran",
+				"customStyles": [{"name": "Fortran", "prefix": "! ", "extensions": ["f90"]}], "includes": ["**/*.f90"]}`), nil)
+
+		configuration, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError).To(BeNil())
+		Expect(configuration.CommentStyle).To(Equal("fortran"))
+		Expect(configuration.ExtensionStyles).To(Equal(map[string]string{"f90": "fortran"}))
+		Expect(configuration.Path).To(Equal(&configurationUri))
+	})
+
+	It("rejects configuration with undeclared comment style", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-file.txt", "style": "fortran", "includes": ["**/*.f90"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(HavePrefix("Error with field 'style': style must be one of the following:"))
+	})
+
+	It("rejects configuration with preambles of an undeclared comment style", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-file.txt", "style": "hash", "stylePreambles": {"Fortran": ["#!.*"]}, "includes": ["**/*.py"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(HavePrefix("Error with field 'stylePreambles': stylePreambles must be one of the following:"))
+	})
+
+	It("rejects configuration with maximum line lengths of an undeclared comment style", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-file.txt", "style": "hash", "styleMaxLineLengths": {"Fortran": 80}, "includes": ["**/*.py"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(HavePrefix("Error with field 'styleMaxLineLengths': styleMaxLineLengths must be one of the following:"))
+	})
+
+	It("rejects configuration with custom comment style named after a built-in one", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-file.txt", "style": "hash", "customStyles": [{"name": "Hash", "prefix": "## "}], "includes": ["**/*.py"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError).To(MatchError("Error with field 'customStyles.0.name': Hash is already defined"))
+	})
+
+	It("rejects configuration with invalid comment style by file extension", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-file.txt", "style": "slashstar", "extensionStyles": {"py": "invalid"}, "includes": ["**/*.go"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).
+			To(HavePrefix("Error with field 'extensionStyles': extensionStyles must be one of the following:"))
+	})
+
+	It("accepts and loads valid configuration with a built-in license instead of a header file", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"license": "apache-2.0", "style": "slashstar", "includes": ["**/*.go"]}`), nil)
+
+		configuration, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError).To(BeNil())
+		Expect(configuration).To(Equal(&core.Configuration{
+			License:      "Apache-2.0",
+			CommentStyle: "slashstar",
+			Includes:     []string{"**/*.go"},
+			Path:         &configurationUri,
+		}))
+	})
+
+	It("accepts and loads valid configuration with SPDX header mode", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"license": "MIT", "headerMode": "SPDX", "style": "slashstar", "includes": ["**/*.go"]}`), nil)
+
+		configuration, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError).To(BeNil())
+		Expect(configuration).To(Equal(&core.Configuration{
+			License:      "MIT",
+			HeaderMode:   "spdx",
+			CommentStyle: "slashstar",
+			Includes:     []string{"**/*.go"},
+			Path:         &configurationUri,
+		}))
+	})
+
+	It("accepts any license expression with SPDX header mode", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"license": "LicenseRef-ACME OR MIT", "headerMode": "spdx", "style": "slashstar", "includes": ["**/*.go"]}`), nil)
+
+		configuration, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError).To(BeNil())
+		Expect(configuration.License).To(Equal("LicenseRef-ACME OR MIT"))
+	})
+
+	It("accepts and loads valid configuration prepending headers to foreign ones", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-file.txt", "foreignHeaders": "Prepend", "style": "slashstar", "includes": ["**/*.go"]}`), nil)
+
+		configuration, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError).To(BeNil())
+		Expect(configuration.ForeignHeaders).To(Equal(core.PrependToForeignHeaders))
+	})
+
+	It("rejects configuration with empty legacy header file", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-file.txt", "legacyHeaderFiles": [""], "style": "slashstar", "includes": ["**/*.go"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(HavePrefix("Error with field 'legacyHeaderFiles.0'"))
+	})
+
+	It("rejects configuration with out of range similarity threshold", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-file.txt", "similarityThreshold": 1.5, "style": "slashstar", "includes": ["**/*.go"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(HavePrefix("Error with field 'similarityThreshold'"))
+	})
+
+	It("rejects configuration with SPDX header mode and no license", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-header.txt", "headerMode": "spdx", "style": "SlashStar", "includes": ["**/*.go"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(Equal("Error with field '(root)': the spdx header mode requires license to be set\n" +
+			"Error with field 'license': license is required"))
+	})
+
+	It("rejects configuration with sidecars and no license", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-header.txt", "sidecars": ["**/*.png"], "style": "SlashStar", "includes": ["**/*.go"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(Equal("Error with field '(root)': license must be set to use sidecars or annotations"))
+	})
+
+	It("rejects configuration with missing header file and license", func() {
 		fileReader.On("Read", configurationUri).
 			Return([]byte(`{"style": "SlashStar", "includes": ["**/*.go"]}`), nil)
 
 		_, validationError := loader.ValidateAndLoad(configurationUri)
 
 		Expect(validationError.Error()).
-			To(HaveSuffix("Error with field 'headerFile': String length must be greater than or equal to 1"))
+			To(HavePrefix("Error with field '(root)': either headerFile or license must be set, but not both"))
+	})
+
+	It("rejects configuration with empty header file", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "", "style": "SlashStar", "includes": ["**/*.go"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(ContainSubstring("headerFile is required"))
+	})
+
+	It("rejects configuration with both header file and license", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-header.txt", "license": "MIT", "style": "SlashStar", "includes": ["**/*.go"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).
+			To(Equal("Error with field '(root)': either headerFile or license must be set, but not both"))
+	})
+
+	It("rejects configuration with unsupported license", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"license": "WTFPL", "style": "SlashStar", "includes": ["**/*.go"]}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(HavePrefix("Error with field 'license': license must be one of the following:"))
 	})
 
 	It("rejects configuration with missing comment style", func() {
@@ -253,6 +458,33 @@ var _ = Describe("Configuration loader", func() {
 		Expect(validationError.Error()).To(HaveSuffix("EndYear is a reserved data parameter and cannot be used"))
 	})
 
+	It("rejects configuration with reserved year list parameter", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-header.txt", "style": "SlashSlash", "includes": ["**/*.*"], "data": {"YearList": "2019"}}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(HaveSuffix("YearList is a reserved data parameter and cannot be used"))
+	})
+
+	It("rejects configuration with invalid author order", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-header.txt", "style": "SlashSlash", "includes": ["**/*.*"], "authors": {"sort": "random"}}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(HavePrefix("Error with field 'authors.sort'"))
+	})
+
+	It("rejects configuration with reserved file parameter", func() {
+		fileReader.On("Read", configurationUri).
+			Return([]byte(`{"headerFile": "some-header.txt", "style": "SlashSlash", "includes": ["**/*.*"], "data": {"Module": "acme"}}`), nil)
+
+		_, validationError := loader.ValidateAndLoad(configurationUri)
+
+		Expect(validationError.Error()).To(HaveSuffix("Module is a reserved data parameter and cannot be used"))
+	})
+
 })
 
 func min(a, b int) int {
@@ -264,6 +496,12 @@ func min(a, b int) int {
 
 type LocalSchemaLoader struct{}
 
+type UnavailableSchemaLoader struct{}
+
+func (*UnavailableSchemaLoader) Load(string) *json.Schema {
+	return nil
+}
+
 func (*LocalSchemaLoader) Load(schemaLocation string) *json.Schema {
 	schema, err := json.NewSchema(json.NewReferenceLoader(schemaLocation))
 	if err != nil {
@@ -17,25 +17,188 @@
 package core
 
 import (
+	"fmt"
 	"github.com/fbiville/headache/internal/pkg/fs"
+	"github.com/fbiville/headache/internal/pkg/license"
 	"github.com/fbiville/headache/internal/pkg/vcs"
 	"log"
+	"path/filepath"
 	"regexp"
+	"strings"
 )
 
 type Configuration struct {
-	HeaderFile   string            `json:"headerFile"`
-	CommentStyle string            `json:"style"`
-	Includes     []string          `json:"includes"`
-	Excludes     []string          `json:"excludes,omitempty"`
-	TemplateData map[string]string `json:"data,omitempty"`
-	Path         *string
+	HeaderFile              string              `json:"headerFile,omitempty"`
+	License                 string              `json:"license,omitempty"`
+	LegacyHeaderFiles       []string            `json:"legacyHeaderFiles,omitempty"`
+	HeaderMode              string              `json:"headerMode,omitempty"`
+	CommentStyle            string              `json:"style"`
+	ExtensionStyles         map[string]string   `json:"extensionStyles,omitempty"`
+	CustomStyles            []CustomStyle       `json:"customStyles,omitempty"`
+	StylePreambles          map[string][]string `json:"stylePreambles,omitempty"`
+	ExtensionPreambles      map[string][]string `json:"extensionPreambles,omitempty"`
+	Includes                []string            `json:"includes"`
+	Excludes                []string            `json:"excludes,omitempty"`
+	Sidecars                []string            `json:"sidecars,omitempty"`
+	Annotations             []string            `json:"annotations,omitempty"`
+	AnnotationHolderKey     string              `json:"annotationHolderKey,omitempty"`
+	ForeignHeaders          string              `json:"foreignHeaders,omitempty"`
+	SimilarityThreshold     float64             `json:"similarityThreshold,omitempty"`
+	Authors                 *AuthorOptions      `json:"authors,omitempty"`
+	MaxLineLength           int                 `json:"maxLineLength,omitempty"`
+	StyleMaxLineLengths     map[string]int      `json:"styleMaxLineLengths,omitempty"`
+	ExtensionMaxLineLengths map[string]int      `json:"extensionMaxLineLengths,omitempty"`
+	TemplateData            map[string]string   `json:"data,omitempty"`
+	Path                    *string             `json:"-"`
 }
 
+const (
+	// the header is the full notice of the license or the contents of the header file
+	FullHeaderMode = "full"
+	// the header is made of the SPDX-FileCopyrightText and SPDX-License-Identifier tags of the license
+	SpdxHeaderMode = "spdx"
+)
+
+const (
+	// files with a foreign copyright or license notice are left untouched
+	SkipForeignHeaders = "skip"
+	// the header is inserted above the foreign copyright or license notice
+	PrependToForeignHeaders = "prepend"
+)
+
 type ChangeSet struct {
 	HeaderContents string
-	HeaderRegex    *regexp.Regexp
-	Files          []vcs.FileChange
+	// header contents of file extensions with a specific comment style, overriding HeaderContents
+	ExtensionHeaderContents map[string]string
+	HeaderRegex             *regexp.Regexp
+	Files                   []vcs.FileChange
+	// detection regex of the current header, used to find stacked copies of the header in repair mode
+	CurrentHeaderRegex *regexp.Regexp
+	// detection regex of the headers holding the configured data values, nil to accept any value
+	// headers matching HeaderRegex but not this one are someone else's, such as the same license with another owner
+	DataHeaderRegex *regexp.Regexp
+	// whether stacked copies of the header are collapsed into one
+	Repair bool
+	// whether the header is inserted above foreign copyright or license notices, instead of skipping these files
+	PrependToForeignHeaders bool
+	// detects reflowed or reworded headers the detection regex misses, nil when fuzzy detection is disabled
+	FuzzyDetector *FuzzyDetector
+	// settings of the {{.Authors}} and {{.AuthorsWithYears}} reserved parameters
+	AuthorOptions *AuthorOptions
+	// comment style of the header, and of file extensions with a specific comment style
+	CommentStyle           CommentStyle
+	ExtensionCommentStyles map[string]CommentStyle
+	// maximum length of the header lines, longer lines are wrapped, 0 to disable wrapping
+	MaxLineLength int
+	// maximum lengths overriding MaxLineLength, by comment style name and by file extension
+	StyleMaxLineLengths     map[string]int
+	ExtensionMaxLineLengths map[string]int
+	// regexes of the leading lines that stay above the header, by comment style name and by file extension
+	StylePreambles     map[string][]*regexp.Regexp
+	ExtensionPreambles map[string][]*regexp.Regexp
+	// uncommented header contents of the sidecar files
+	SidecarContents string
+	// files that cannot carry comments, their header is written to a sidecar file
+	SidecarFiles []vcs.FileChange
+	// sidecar files whose source file does not exist anymore
+	OrphanedSidecars []string
+}
+
+// extension of the REUSE sidecar files, holding the header of files that cannot carry comments
+const SidecarExtension = ".license"
+
+// Returns the path of the sidecar file of the given file
+func SidecarPath(path string) string {
+	return path + SidecarExtension
+}
+
+// Describes where the header template comes from, either the header file or the built-in license
+func (configuration *Configuration) HeaderSource() string {
+	if configuration.HeaderMode == SpdxHeaderMode {
+		return fmt.Sprintf("of license %s (SPDX tags)", configuration.License)
+	}
+	if configuration.License != "" {
+		return fmt.Sprintf("of license %s", configuration.License)
+	}
+	return configuration.HeaderFile
+}
+
+// Reads the configured header template, either from the header file or from the built-in licenses
+func ReadHeaderTemplate(reader fs.FileReader, configuration *Configuration) (string, error) {
+	if configuration.HeaderMode == SpdxHeaderMode {
+		return license.SpdxHeader(configuration.License)
+	}
+	if configuration.License != "" {
+		return license.Header(configuration.License)
+	}
+	headerBytes, err := reader.Read(configuration.HeaderFile)
+	if err != nil {
+		return "", err
+	}
+	return string(headerBytes), nil
+}
+
+// Reads the configured legacy header templates, parameterized with the current template data
+func ReadLegacyTemplates(reader fs.FileReader, configuration *Configuration) ([]*HeaderTemplate, error) {
+	result := make([]*HeaderTemplate, 0, len(configuration.LegacyHeaderFiles))
+	for _, path := range configuration.LegacyHeaderFiles {
+		headerBytes, err := reader.Read(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read legacy header template %v: %w", path, err)
+		}
+		result = append(result, template(string(headerBytes), configuration))
+	}
+	return result, nil
+}
+
+// Returns the header contents to insert in the file, based on its extension
+func (changeSet *ChangeSet) HeaderContentsOf(path string) string {
+	if contents, found := changeSet.ExtensionHeaderContents[FileExtension(path)]; found {
+		return contents
+	}
+	return changeSet.HeaderContents
+}
+
+func (changeSet *ChangeSet) CommentStyleOf(path string) CommentStyle {
+	if style, found := changeSet.ExtensionCommentStyles[FileExtension(path)]; found {
+		return style
+	}
+	return changeSet.CommentStyle
+}
+
+// Returns the maximum length of the header lines of the given file, based on its extension first, then on its
+// comment style, and falling back to the global maximum length
+func (changeSet *ChangeSet) MaxLineLengthOf(path string) int {
+	if maxLineLength, found := changeSet.ExtensionMaxLineLengths[FileExtension(path)]; found {
+		return maxLineLength
+	}
+	if style := changeSet.CommentStyleOf(path); style != nil {
+		if maxLineLength, found := changeSet.StyleMaxLineLengths[strings.ToLower(style.GetName())]; found {
+			return maxLineLength
+		}
+	}
+	return changeSet.MaxLineLength
+}
+
+func trimExtensionDots(maxLineLengths map[string]int) map[string]int {
+	if maxLineLengths == nil {
+		return nil
+	}
+	result := make(map[string]int, len(maxLineLengths))
+	for extension, maxLineLength := range maxLineLengths {
+		result[strings.TrimPrefix(extension, ".")] = maxLineLength
+	}
+	return result
+}
+
+// checks whether the detected header holds the configured data values, rather than someone else's
+func (changeSet *ChangeSet) matchesData(header string) bool {
+	return changeSet.DataHeaderRegex == nil || changeSet.DataHeaderRegex.MatchString(header)
+}
+
+// Returns the extension of the file, without leading dot
+func FileExtension(path string) string {
+	return strings.TrimPrefix(filepath.Ext(path), ".")
 }
 
 type ConfigurationResolver struct {
@@ -44,55 +207,220 @@ type ConfigurationResolver struct {
 	PathMatcher      fs.PathMatcher
 }
 
+// Resolves the files changed since the last execution, or all the included files if the configuration changed since
 func (resolver *ConfigurationResolver) ResolveEagerly(currentConfig *Configuration) (*ChangeSet, error) {
+	return resolver.resolve(currentConfig, false)
+}
+
+// Resolves all the included files, regardless of the changes since the last execution
+// The header template of the last execution is still used to detect outdated headers
+func (resolver *ConfigurationResolver) ResolveFully(currentConfig *Configuration) (*ChangeSet, error) {
+	return resolver.resolve(currentConfig, true)
+}
+
+func (resolver *ConfigurationResolver) resolve(currentConfig *Configuration, fullScan bool) (*ChangeSet, error) {
 	versionedTemplate, err := resolver.ExecutionTracker.RetrieveVersionedTemplate(currentConfig)
 	if err != nil {
 		return nil, err
 	}
+	changeSet, err := newChangeSet(currentConfig, versionedTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, sidecarChanges, err := resolver.getAffectedFiles(currentConfig, versionedTemplate, fullScan)
+	if err != nil {
+		return nil, err
+	}
+	changeSet.Files = changes
+	if len(currentConfig.Sidecars) > 0 {
+		if err := resolver.resolveSidecars(currentConfig, sidecarChanges, changeSet); err != nil {
+			return nil, err
+		}
+	}
+	return changeSet, nil
+}
+
+// Resolves the detection regex of the current header template and all the included files, regardless of
+// previous executions
+func (resolver *ConfigurationResolver) ResolveAll(currentConfig *Configuration) (*ChangeSet, error) {
+	header, err := ReadHeaderTemplate(resolver.Environment.FileSystem.FileReader, currentConfig)
+	if err != nil {
+		return nil, err
+	}
+	currentTemplate := template(header, currentConfig)
+	legacyTemplates, err := ReadLegacyTemplates(resolver.Environment.FileSystem.FileReader, currentConfig)
+	if err != nil {
+		return nil, err
+	}
+	versionedTemplate := &VersionedHeaderTemplate{Current: currentTemplate, Previous: currentTemplate, Legacy: legacyTemplates}
+	changeSet, err := newChangeSet(currentConfig, versionedTemplate)
+	if err != nil {
+		return nil, err
+	}
 
-	contents, err := ParseTemplate(versionedTemplate, ParseCommentStyle(currentConfig.CommentStyle))
+	changeSet.Files, err = resolver.PathMatcher.ScanAllFiles(currentConfig.Includes, currentConfig.Excludes, resolver.Environment.FileSystem)
 	if err != nil {
 		return nil, err
 	}
+	return changeSet, nil
+}
 
-	changes, err := resolver.getAffectedFiles(currentConfig, versionedTemplate)
+// builds the change set of the given header template, without any file
+func newChangeSet(currentConfig *Configuration, versionedTemplate *VersionedHeaderTemplate) (*ChangeSet, error) {
+	customStyles := CustomCommentStyles(currentConfig.CustomStyles)
+	commentStyle := ParseCommentStyle(currentConfig.CommentStyle, customStyles...)
+	contents, err := ParseTemplate(versionedTemplate, commentStyle)
+	if err != nil {
+		return nil, err
+	}
+	extensionContents, err := parseExtensionTemplates(versionedTemplate, currentConfig.ExtensionStyles, customStyles)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ChangeSet{
-		HeaderContents: contents.ActualContent,
-		HeaderRegex:    contents.DetectionRegex,
-		Files:          changes,
-	}, nil
+	changeSet := &ChangeSet{
+		HeaderContents:          contents.ActualContent,
+		ExtensionHeaderContents: extensionContents,
+		HeaderRegex:             contents.DetectionRegex,
+		CurrentHeaderRegex:      contents.CurrentDetectionRegex,
+		DataHeaderRegex:         contents.DataDetectionRegex,
+		PrependToForeignHeaders: currentConfig.ForeignHeaders == PrependToForeignHeaders,
+		AuthorOptions:           currentConfig.Authors,
+		CommentStyle:            commentStyle,
+		ExtensionCommentStyles:  parseExtensionStyles(currentConfig.ExtensionStyles, customStyles),
+		MaxLineLength:           currentConfig.MaxLineLength,
+		StyleMaxLineLengths:     currentConfig.StyleMaxLineLengths,
+		ExtensionMaxLineLengths: trimExtensionDots(currentConfig.ExtensionMaxLineLengths),
+	}
+	changeSet.StylePreambles, err = compileStylePreambles(currentConfig.StylePreambles, SupportedStyles(customStyles...))
+	if err != nil {
+		return nil, err
+	}
+	changeSet.ExtensionPreambles, err = compileExtensionPreambles(currentConfig.ExtensionPreambles)
+	if err != nil {
+		return nil, err
+	}
+	if currentConfig.SimilarityThreshold > 0 {
+		changeSet.FuzzyDetector, err = NewFuzzyDetector(currentConfig.SimilarityThreshold, versionedTemplate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return changeSet, nil
+}
+
+func parseExtensionTemplates(versionedTemplate *VersionedHeaderTemplate, extensionStyles map[string]string, customStyles []CommentStyle) (map[string]string, error) {
+	result := make(map[string]string, len(extensionStyles))
+	for extension, styleName := range extensionStyles {
+		contents, err := ParseTemplate(versionedTemplate, ParseCommentStyle(styleName, customStyles...))
+		if err != nil {
+			return nil, err
+		}
+		result[strings.TrimPrefix(extension, ".")] = contents.ActualContent
+	}
+	return result, nil
+}
+
+func parseExtensionStyles(extensionStyles map[string]string, customStyles []CommentStyle) map[string]CommentStyle {
+	result := make(map[string]CommentStyle, len(extensionStyles))
+	for extension, styleName := range extensionStyles {
+		result[strings.TrimPrefix(extension, ".")] = ParseCommentStyle(styleName, customStyles...)
+	}
+	return result
 }
 
-func (resolver *ConfigurationResolver) getAffectedFiles(config *Configuration, versionedTemplate *VersionedHeaderTemplate) ([]vcs.FileChange, error) {
+func (resolver *ConfigurationResolver) resolveSidecars(config *Configuration, sidecarChanges []vcs.FileChange, changeSet *ChangeSet) error {
+	header, err := license.SpdxHeader(config.License)
+	if err != nil {
+		return err
+	}
+	contents, err := parseUncommentedTemplate(template(header, config))
+	if err != nil {
+		return err
+	}
+	orphans, err := resolver.findOrphanedSidecars(config)
+	if err != nil {
+		return err
+	}
+	changeSet.SidecarContents = contents
+	changeSet.SidecarFiles = sidecarChanges
+	changeSet.OrphanedSidecars = orphans
+	return nil
+}
+
+func (resolver *ConfigurationResolver) findOrphanedSidecars(config *Configuration) ([]string, error) {
+	fileSystem := resolver.Environment.FileSystem
+	sidecarPatterns := make([]string, len(config.Sidecars))
+	for i, pattern := range config.Sidecars {
+		sidecarPatterns[i] = SidecarPath(pattern)
+	}
+	sidecars, err := resolver.PathMatcher.ScanAllFiles(sidecarPatterns, config.Excludes, fileSystem)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0)
+	for _, sidecar := range sidecars {
+		if !fileSystem.IsFile(strings.TrimSuffix(sidecar.Path, SidecarExtension)) {
+			result = append(result, sidecar.Path)
+		}
+	}
+	return result, nil
+}
+
+func (resolver *ConfigurationResolver) getAffectedFiles(config *Configuration, versionedTemplate *VersionedHeaderTemplate, fullScan bool) ([]vcs.FileChange, []vcs.FileChange, error) {
 	versioningClient := resolver.Environment.VersioningClient
 	fileSystem := resolver.Environment.FileSystem
 	var (
-		changes []vcs.FileChange
-		err     error
+		changes        []vcs.FileChange
+		sidecarChanges []vcs.FileChange
+		err            error
 	)
 
-	if versionedTemplate.RequiresFullScan() {
-		if versionedTemplate.Revision == "" {
+	if fullScan || versionedTemplate.RequiresFullScan() {
+		if fullScan {
+			log.Print("Scanning all the included files")
+		} else if versionedTemplate.Revision == "" {
 			log.Print("Unable to get last execution revision, triggering a full scan")
 		} else {
 			log.Printf("Configuration and/or license header template changed since last execution (%s), triggering a full scan", versionedTemplate.Revision)
 		}
 		changes, err = resolver.PathMatcher.ScanAllFiles(config.Includes, config.Excludes, fileSystem)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if len(config.Sidecars) > 0 {
+			sidecarChanges, err = resolver.PathMatcher.ScanAllFiles(config.Sidecars, config.Excludes, fileSystem)
+			if err != nil {
+				return nil, nil, err
+			}
 		}
 	} else {
 		revision := versionedTemplate.Revision
 		log.Printf("Scanning changes since revision %s", revision)
 		fileChanges, err := versioningClient.GetChanges(revision)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		changes = resolver.PathMatcher.MatchFiles(fileChanges, config.Includes, config.Excludes, fileSystem)
+		if len(config.Sidecars) > 0 {
+			sidecarChanges = resolver.PathMatcher.MatchFiles(fileChanges, config.Sidecars, config.Excludes, fileSystem)
+		}
+	}
+	changes, err = versioningClient.AddMetadata(changes, resolver.Environment.Clock)
+	if err != nil || len(config.Sidecars) == 0 {
+		return changes, nil, err
+	}
+	// sidecar files are not sources themselves
+	sources := make([]vcs.FileChange, 0, len(sidecarChanges))
+	for _, change := range sidecarChanges {
+		if !strings.HasSuffix(change.Path, SidecarExtension) {
+			sources = append(sources, change)
+		}
+	}
+	sidecarChanges, err = versioningClient.AddMetadata(sources, resolver.Environment.Clock)
+	if err != nil {
+		return nil, nil, err
 	}
-	return versioningClient.AddMetadata(changes, resolver.Environment.Clock)
+	return changes, sidecarChanges, nil
 }
@@ -17,6 +17,7 @@
 package core_test
 
 import (
+	"os"
 	"strings"
 
 	"github.com/fbiville/headache/internal/pkg/core"
@@ -239,6 +240,61 @@ var _ = Describe("Configuration parser", func() {
 		Expect(onlyPaths(changeSet.Files)).To(Equal([]FileChange{{Path: "hello-world.go"}}))
 	})
 
+	It("pre-computes the header contents of file extensions with a specific comment style", func() {
+		configuration := &core.Configuration{
+			HeaderFile:      "some-header",
+			CommentStyle:    "SlashStar",
+			ExtensionStyles: map[string]string{".sh": "Hash", "sql": "DashDash"},
+			Includes:        includes,
+			Excludes:        excludes,
+			TemplateData:    data,
+		}
+		tracker.On("RetrieveVersionedTemplate", configuration).
+			Return(unchangedHeaderContents("Copyright {{.Year}} {{.Owner}}", data, revision), nil)
+		versioningClient.On("GetChanges", revision).Return(initialChanges, nil)
+		pathMatcher.On("MatchFiles", initialChanges, includes, excludes, fileSystem).Return(resultingChanges)
+		versioningClient.On("AddMetadata", resultingChanges, clock).Return(resultingChanges, nil)
+
+		changeSet, err := configurationResolver.ResolveEagerly(configuration)
+
+		Expect(err).To(BeNil())
+		Expect(changeSet.HeaderContentsOf("hello-world.go")).To(Equal("/*\n * Copyright {{.YearRange}} ACME Labs\n */"))
+		Expect(changeSet.HeaderContentsOf("scripts/hello-world.sh")).To(Equal("# Copyright {{.YearRange}} ACME Labs"))
+		Expect(changeSet.HeaderContentsOf("hello-world.sql")).To(Equal("-- Copyright {{.YearRange}} ACME Labs"))
+	})
+
+	It("pre-computes the sidecar contents and files of files that cannot carry comments", func() {
+		sidecars := []string{"**/*.png"}
+		configuration := &core.Configuration{
+			License:      "MIT",
+			CommentStyle: "SlashSlash",
+			Includes:     includes,
+			Excludes:     excludes,
+			Sidecars:     sidecars,
+			TemplateData: data,
+		}
+		sidecarChanges := []FileChange{{Path: "logo.png"}, {Path: "logo.png.license"}}
+		sidecarSources := []FileChange{{Path: "logo.png"}}
+		tracker.On("RetrieveVersionedTemplate", configuration).
+			Return(unchangedHeaderContents("Copyright {{.Year}} {{.Owner}}", data, revision), nil)
+		versioningClient.On("GetChanges", revision).Return(initialChanges, nil)
+		pathMatcher.On("MatchFiles", initialChanges, includes, excludes, fileSystem).Return(resultingChanges)
+		pathMatcher.On("MatchFiles", initialChanges, sidecars, excludes, fileSystem).Return(sidecarChanges)
+		versioningClient.On("AddMetadata", resultingChanges, clock).Return(resultingChanges, nil)
+		versioningClient.On("AddMetadata", sidecarSources, clock).Return(sidecarSources, nil)
+		pathMatcher.On("ScanAllFiles", []string{"**/*.png.license"}, excludes, fileSystem).
+			Return([]FileChange{{Path: "logo.png.license"}, {Path: "deleted.png.license"}}, nil)
+		fileReader.On("Stat", "logo.png").Return(&fs.FakeFileInfo{FileMode: 0644}, nil)
+		fileReader.On("Stat", "deleted.png").Return(nil, os.ErrNotExist)
+
+		changeSet, err := configurationResolver.ResolveEagerly(configuration)
+
+		Expect(err).To(BeNil())
+		Expect(changeSet.SidecarContents).To(Equal("SPDX-FileCopyrightText: {{.YearRange}} ACME Labs\nSPDX-License-Identifier: MIT"))
+		Expect(changeSet.SidecarFiles).To(Equal(sidecarSources))
+		Expect(changeSet.OrphanedSidecars).To(Equal([]string{"deleted.png.license"}))
+	})
+
 	It("pre-computes a regex that allows to detect headers", func() {
 		configuration := &core.Configuration{
 			HeaderFile:   "some-header",
@@ -274,6 +330,26 @@ var _ = Describe("Configuration parser", func() {
 			"Regex should match contents with different data and comment style")
 	})
 
+	It("resolves all the included files when asked to, even though the configuration did not change", func() {
+		configuration := &core.Configuration{
+			HeaderFile:   "some-header",
+			CommentStyle: "SlashSlash",
+			Includes:     includes,
+			Excludes:     excludes,
+			TemplateData: data,
+		}
+		tracker.On("RetrieveVersionedTemplate", configuration).
+			Return(unchangedHeaderContents("Copyright {{.Year}} {{.Owner}}", data, revision), nil)
+		pathMatcher.On("ScanAllFiles", includes, excludes, fileSystem).Return(resultingChanges, nil)
+		versioningClient.On("AddMetadata", resultingChanges, clock).Return(resultingChanges, nil)
+
+		changeSet, err := configurationResolver.ResolveFully(configuration)
+
+		Expect(err).To(BeNil())
+		Expect(changeSet.Files).To(Equal(resultingChanges))
+		versioningClient.AssertNotCalled(t, "GetChanges", revision)
+	})
+
 	It("computes the header regex based on previous configuration", func() {
 		configuration := &core.Configuration{
 			HeaderFile:   "some-header",
@@ -300,6 +376,140 @@ var _ = Describe("Configuration parser", func() {
 	})
 })
 
+var _ = Describe("Configuration parser for all files", func() {
+	var (
+		t                     GinkgoTInterface
+		fileReader            *fs_mocks.FileReader
+		fileSystem            *fs.FileSystem
+		tracker               *core_mocks.ExecutionTracker
+		pathMatcher           *fs_mocks.PathMatcher
+		includes              []string
+		excludes              []string
+		configurationResolver *core.ConfigurationResolver
+	)
+
+	BeforeEach(func() {
+		t = GinkgoT()
+		fileReader = new(fs_mocks.FileReader)
+		fileSystem = &fs.FileSystem{FileReader: fileReader}
+		tracker = new(core_mocks.ExecutionTracker)
+		pathMatcher = new(fs_mocks.PathMatcher)
+		includes = []string{"**/*.go"}
+		excludes = []string{"vendor/**/*"}
+		configurationResolver = &core.ConfigurationResolver{
+			Environment:      &core.Environment{FileSystem: fileSystem},
+			ExecutionTracker: tracker,
+			PathMatcher:      pathMatcher,
+		}
+	})
+
+	AfterEach(func() {
+		fileReader.AssertExpectations(t)
+		tracker.AssertExpectations(t)
+		pathMatcher.AssertExpectations(t)
+	})
+
+	It("detects the current header in all included files", func() {
+		configuration := &core.Configuration{
+			HeaderFile:   "some-header",
+			CommentStyle: "SlashSlash",
+			Includes:     includes,
+			Excludes:     excludes,
+			TemplateData: map[string]string{"Owner": "ACME Labs"},
+		}
+		allFiles := []FileChange{{Path: "main.go"}, {Path: "pkg/lib.go"}}
+		fileReader.On("Read", "some-header").Return([]byte("Copyright {{.Year}} {{.Owner}}"), nil)
+		pathMatcher.On("ScanAllFiles", includes, excludes, fileSystem).Return(allFiles, nil)
+
+		changeSet, err := configurationResolver.ResolveAll(configuration)
+
+		Expect(err).To(BeNil())
+		Expect(changeSet.Files).To(Equal(allFiles))
+		Expect(changeSet.HeaderRegex.MatchString("/*\n * Copyright 2014-2019 ACME Labs\n */")).To(BeTrue(),
+			"Regex should match current header")
+	})
+
+	It("detects the current and legacy headers in all included files", func() {
+		configuration := &core.Configuration{
+			HeaderFile:        "some-header",
+			LegacyHeaderFiles: []string{"old-header"},
+			CommentStyle:      "SlashSlash",
+			Includes:          includes,
+			Excludes:          excludes,
+			TemplateData:      map[string]string{"Owner": "ACME Labs"},
+		}
+		allFiles := []FileChange{{Path: "main.go"}}
+		fileReader.On("Read", "some-header").Return([]byte("Copyright {{.Year}} {{.Owner}}"), nil)
+		fileReader.On("Read", "old-header").Return([]byte("(c) {{.Owner}}, all rights reserved"), nil)
+		pathMatcher.On("ScanAllFiles", includes, excludes, fileSystem).Return(allFiles, nil)
+
+		changeSet, err := configurationResolver.ResolveAll(configuration)
+
+		Expect(err).To(BeNil())
+		Expect(changeSet.HeaderRegex.MatchString("// Copyright 2019 ACME Labs")).To(BeTrue(), "Regex should match current header")
+		Expect(changeSet.HeaderRegex.MatchString("// (c) ACME Labs, all rights reserved")).To(BeTrue(), "Regex should match legacy header")
+	})
+
+	It("only detects the current header holding the configured data in all included files", func() {
+		configuration := &core.Configuration{
+			HeaderFile:   "some-header",
+			CommentStyle: "SlashSlash",
+			Includes:     includes,
+			Excludes:     excludes,
+			TemplateData: map[string]string{"Owner": "ACME Labs"},
+		}
+		fileReader.On("Read", "some-header").Return([]byte("Copyright {{.Year}} {{.Owner}}"), nil)
+		pathMatcher.On("ScanAllFiles", includes, excludes, fileSystem).Return([]FileChange{{Path: "main.go"}}, nil)
+
+		changeSet, err := configurationResolver.ResolveAll(configuration)
+
+		Expect(err).To(BeNil())
+		Expect(changeSet.DataHeaderRegex.MatchString("// Copyright 2019 ACME Labs")).To(BeTrue(), "Regex should match own header")
+		Expect(changeSet.DataHeaderRegex.MatchString("// Copyright 2019 Someone Else")).To(BeFalse(), "Regex should not match foreign header")
+	})
+
+	It("resolves the preambles and fuzzy detection settings of the header to detect", func() {
+		configuration := &core.Configuration{
+			HeaderFile:          "some-header",
+			CommentStyle:        "Hash",
+			StylePreambles:      map[string][]string{"hash": {"^#!"}},
+			SimilarityThreshold: 0.8,
+			Includes:            includes,
+			Excludes:            excludes,
+			TemplateData:        map[string]string{"Owner": "ACME Labs"},
+		}
+		fileReader.On("Read", "some-header").Return([]byte("Copyright {{.Year}} {{.Owner}}"), nil)
+		pathMatcher.On("ScanAllFiles", includes, excludes, fileSystem).Return([]FileChange{{Path: "run.sh"}}, nil)
+
+		changeSet, err := configurationResolver.ResolveAll(configuration)
+
+		Expect(err).To(BeNil())
+		Expect(changeSet.PreambleOf("run.sh")).To(HaveLen(1))
+		Expect(changeSet.FuzzyDetector).NotTo(BeNil())
+	})
+
+	It("detects the current SPDX header of the built-in license in all included files", func() {
+		configuration := &core.Configuration{
+			License:      "MIT",
+			HeaderMode:   core.SpdxHeaderMode,
+			CommentStyle: "SlashSlash",
+			Includes:     includes,
+			Excludes:     excludes,
+			TemplateData: map[string]string{"Owner": "ACME Labs"},
+		}
+		allFiles := []FileChange{{Path: "main.go"}}
+		pathMatcher.On("ScanAllFiles", includes, excludes, fileSystem).Return(allFiles, nil)
+
+		changeSet, err := configurationResolver.ResolveAll(configuration)
+
+		Expect(err).To(BeNil())
+		Expect(changeSet.HeaderContents).To(Equal("// SPDX-FileCopyrightText: {{.YearRange}} ACME Labs\n" +
+			"// SPDX-License-Identifier: MIT"))
+		Expect(changeSet.HeaderRegex.MatchString("# SPDX-FileCopyrightText: 2014-2019 ACME Labs\n# SPDX-License-Identifier: MIT")).
+			To(BeTrue(), "Regex should match current header")
+	})
+})
+
 func unchangedHeaderContents(lines string, data map[string]string, revision string) *core.VersionedHeaderTemplate {
 	unchangedTemplate := template(lines, data)
 	return &core.VersionedHeaderTemplate{
@@ -42,6 +42,8 @@ type ExecutionTracker interface {
 	//
 	// If this is the first execution of headache, the previous execution's template
 	// is set to the currently configured one and the revision is left empty.
+	//
+	// The configured legacy header templates are retrieved as well, as they are to be replaced by (1) too.
 	RetrieveVersionedTemplate(configuration *Configuration) (*VersionedHeaderTemplate, error)
 
 	// Persists current execution's data to the execution tracker file
@@ -51,6 +53,24 @@ type ExecutionTracker interface {
 type HeaderTemplate struct {
 	Lines []string
 	Data  map[string]string
+	// SPDX identifier of the built-in license the lines come from, if any
+	License string
+	// paths of the legacy header templates configured along with this one
+	LegacyHeaderFiles []string
+	// settings of the author parameters configured along with this one
+	Authors *AuthorOptions
+	// maximum length of the header lines, 0 if the lines are not wrapped
+	MaxLineLength int
+	// maximum lengths overriding MaxLineLength, by comment style name and by file extension
+	StyleMaxLineLengths     map[string]int
+	ExtensionMaxLineLengths map[string]int
+	// comment styles declared in the configuration, along with the built-in ones
+	CustomStyles []CustomStyle
+}
+
+// checks whether the header lines of some files may have been wrapped
+func (header *HeaderTemplate) wrapsLines() bool {
+	return header.MaxLineLength > 0 || len(header.StyleMaxLineLengths) > 0 || len(header.ExtensionMaxLineLengths) > 0
 }
 
 type ExecutionVcsTracker struct {
@@ -74,9 +94,14 @@ func (evt *ExecutionVcsTracker) RetrieveVersionedTemplate(currentConfiguration *
 		log.Printf("Assuming unchanged configuration and header")
 		formerTemplate = currentTemplate
 	}
+	legacyTemplates, err := ReadLegacyTemplates(evt.FileSystem.FileReader, currentConfiguration)
+	if err != nil {
+		return nil, err
+	}
 	return &VersionedHeaderTemplate{
 		Current:  currentTemplate,
 		Previous: formerTemplate,
+		Legacy:   legacyTemplates,
 		Revision: revision,
 	}, nil
 }
@@ -95,26 +120,26 @@ func (evt *ExecutionVcsTracker) TrackExecution(configurationPath *string) error
 	if err != nil {
 		return fmt.Errorf("cannot unmarshal configuration %v: %w", *configurationPath, err)
 	}
-	header, err := evt.FileSystem.FileReader.Read(configuration.HeaderFile)
+	header, err := ReadHeaderTemplate(evt.FileSystem.FileReader, configuration)
 	if err != nil {
-		return fmt.Errorf("cannot read header template %v: %w", configuration.HeaderFile, err)
+		return fmt.Errorf("cannot read header template %v: %w", configuration.HeaderSource(), err)
 	}
 	contents := fmt.Sprintf(`# Generated by headache | %d -- commit me!
 encoded_configuration:%s
 encoded_header:%s
 `, timestamp,
 		base64.StdEncoding.EncodeToString(configurationContents),
-		base64.StdEncoding.EncodeToString(header),
+		base64.StdEncoding.EncodeToString([]byte(header)),
 	)
 	return evt.FileSystem.FileWriter.Write(trackerPath, contents, 0640)
 }
 
 func (evt *ExecutionVcsTracker) readCurrentTemplate(configuration *Configuration) (*HeaderTemplate, error) {
-	headerBytes, err := evt.FileSystem.FileReader.Read(configuration.HeaderFile)
+	header, err := ReadHeaderTemplate(evt.FileSystem.FileReader, configuration)
 	if err != nil {
 		return nil, err
 	}
-	return template(string(headerBytes), configuration.TemplateData), nil
+	return template(header, configuration), nil
 }
 
 func (evt *ExecutionVcsTracker) readFormerTemplate(currentConfiguration *Configuration) (*HeaderTemplate, string, error) {
@@ -184,7 +209,7 @@ func (evt *ExecutionVcsTracker) readFormerTemplateByConfiguration(trackerContent
 	if err != nil {
 		return nil, fmt.Errorf("could not decode encoded header template: %w", err)
 	}
-	return template(string(previousHeader), previousConfiguration.TemplateData), nil
+	return template(string(previousHeader), previousConfiguration), nil
 }
 
 // Reads the configuration and header content from the serialized configuration path at the latest revision associated
@@ -218,11 +243,11 @@ func (evt *ExecutionVcsTracker) readFormerTemplateByVersioning(trackerContents s
 	if err != nil {
 		return nil, fmt.Errorf("cannot unmarshal configuration at revision %s: %w", lastExecutionRevision, err)
 	}
-	previousHeader, err := evt.FileSystem.FileReader.Read(previousConfiguration.HeaderFile)
+	previousHeader, err := ReadHeaderTemplate(evt.FileSystem.FileReader, previousConfiguration)
 	if err != nil {
 		return nil, fmt.Errorf("cannot read former configuration's header at revision %s: %w", lastExecutionRevision, err)
 	}
-	return template(string(previousHeader), previousConfiguration.TemplateData), nil
+	return template(previousHeader, previousConfiguration), nil
 }
 
 func (evt *ExecutionVcsTracker) getLegacyExecutionConfigurationPath(trackingContents string) (string, error) {
@@ -263,9 +288,16 @@ func matchValueByKeyInLine(key string, contents string) ([]string, error) {
 	}
 }
 
-func template(contents string, data map[string]string) *HeaderTemplate {
+func template(contents string, configuration *Configuration) *HeaderTemplate {
 	return &HeaderTemplate{
-		Lines: strings.Split(contents, "\n"),
-		Data:  data,
+		Lines:                   strings.Split(withLineEnding(contents, "\n"), "\n"),
+		Data:                    configuration.TemplateData,
+		License:                 configuration.License,
+		LegacyHeaderFiles:       configuration.LegacyHeaderFiles,
+		Authors:                 configuration.Authors,
+		MaxLineLength:           configuration.MaxLineLength,
+		StyleMaxLineLengths:     configuration.StyleMaxLineLengths,
+		ExtensionMaxLineLengths: configuration.ExtensionMaxLineLengths,
+		CustomStyles:            configuration.CustomStyles,
 	}
 }
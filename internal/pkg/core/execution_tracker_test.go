@@ -108,6 +108,39 @@ var _ = Describe("The execution tracker", func() {
 				})
 			})
 
+			Context("with legacy header files", func() {
+				BeforeEach(func() {
+					currentConfiguration.LegacyHeaderFiles = []string{"legacy-header-file"}
+					fileReader.On("Read", currentHeaderFile).
+						Return([]byte(currentHeaderContents), nil)
+					vcs.On("Root").
+						Return(fakeRepositoryRoot, nil)
+					fileReader.On("Stat", trackerFilePath).
+						Return(nil, os.ErrNotExist)
+				})
+
+				It("returns the legacy contents with the current data", func() {
+					fileReader.On("Read", "legacy-header-file").
+						Return([]byte("some\nlegacy header"), nil)
+
+					versionedTemplate, err := tracker.RetrieveVersionedTemplate(currentConfiguration)
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(versionedTemplate.Legacy).To(HaveLen(1))
+					Expect(versionedTemplate.Legacy[0].Data).To(Equal(currentData))
+					Expect(versionedTemplate.Legacy[0].Lines).To(Equal([]string{"some", "legacy header"}))
+				})
+
+				It("forwards the error when reading them", func() {
+					fileReader.On("Read", "legacy-header-file").
+						Return(nil, os.ErrNotExist)
+
+					_, err := tracker.RetrieveVersionedTemplate(currentConfiguration)
+
+					Expect(err).To(MatchError("cannot read legacy header template legacy-header-file: file does not exist"))
+				})
+			})
+
 			Context("with an error when reading current header file", func() {
 				expectedErr := fmt.Errorf("current header error")
 
@@ -355,6 +388,26 @@ var _ = Describe("The execution tracker", func() {
 					})
 				})
 
+				Context("with a built-in license", func() {
+					BeforeEach(func() {
+						fileReader.On("Read", trackerFilePath).
+							Return([]byte(fmt.Sprintf("# Generated by headache | 1547741491 -- commit me!\nencoded_configuration:%s\nencoded_header:%s",
+								base64Encode(`{"license": "MIT", "style": "SlashStar", "includes": ["**/*.go"]}`),
+								base64Encode(previousHeaderContents))), nil)
+						vcs.On("LatestRevision", trackerFilePath).
+							Return(lastExecutionRevision, nil)
+					})
+
+					It("reads the encoded license identifier", func() {
+						versionedTemplate, err := tracker.RetrieveVersionedTemplate(currentConfiguration)
+
+						Expect(err).NotTo(HaveOccurred())
+						Expect(versionedTemplate.Current.License).To(BeEmpty())
+						Expect(versionedTemplate.Previous.License).To(Equal("MIT"))
+						Expect(versionedTemplate.RequiresFullScan()).To(BeTrue())
+					})
+				})
+
 				Context("with an error when reading misencoded configuration", func() {
 					BeforeEach(func() {
 						fileReader.On("Read", trackerFilePath).
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fbiville/headache/internal/pkg/vcs"
+)
+
+var (
+	// package declarations of Go, Java, Kotlin and Scala files
+	packageRegex = regexp.MustCompile(`(?m)^[\t ]*package[\t ]+([\w.]+)`)
+	moduleRegex  = regexp.MustCompile(`(?m)^[\t ]*module[\t ]+"?([^"\s]+)"?`)
+)
+
+// names of the reserved parameters specific to each file
+var fileParameterNames = []string{"FilePath", "FileName", "Extension", "Directory", "Package", "Module", "Authors",
+	"AuthorsWithYears", "HeaderDetected"}
+
+// computes the reserved parameters of the given file, substituted in the second pass of the template
+// the package and module are only resolved when the template references them
+func (headache *Headache) fileParameters(template string, change *vcs.FileChange, contents string, authorOptions *AuthorOptions) map[string]string {
+	path := change.Path
+	directory := filepath.Dir(path)
+	result := map[string]string{
+		"FilePath":  filepath.ToSlash(path),
+		"FileName":  filepath.Base(path),
+		"Extension": strings.TrimPrefix(filepath.Ext(path), "."),
+		"Directory": directoryName(directory),
+		"Package":   "",
+		"Module":    "",
+	}
+	if strings.Contains(template, ".Package") {
+		if matches := packageRegex.FindStringSubmatch(contents); matches != nil {
+			result["Package"] = matches[1]
+		}
+	}
+	if strings.Contains(template, ".Module") {
+		result["Module"] = headache.nearestModule(directory)
+	}
+	for name, value := range authorParameters(change.Authors, authorOptions) {
+		result[name] = value
+	}
+	return result
+}
+
+// returns the module path declared by the nearest go.mod file, looking up from the given directory
+func (headache *Headache) nearestModule(directory string) string {
+	for {
+		goMod := filepath.Join(directory, "go.mod")
+		if headache.Fs.IsFile(goMod) {
+			bytes, err := headache.Fs.FileReader.Read(goMod)
+			if err != nil {
+				return ""
+			}
+			if matches := moduleRegex.FindStringSubmatch(string(bytes)); matches != nil {
+				return matches[1]
+			}
+			return ""
+		}
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return ""
+		}
+		directory = parent
+	}
+}
+
+func directoryName(directory string) string {
+	if directory == "." {
+		if absolutePath, err := filepath.Abs(directory); err == nil {
+			return filepath.Base(absolutePath)
+		}
+	}
+	return filepath.Base(directory)
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"regexp"
+	"strings"
+)
+
+// matches the usual wording of copyright and license notices
+// "(c)" must stand on its own, so that calls such as f(c) are not mistaken for a notice
+var noticeRegex = regexp.MustCompile(`(?im)\bcopyright\b|(?:^|\s)\(c\)(?:\s|$)|©|\bspdx-license-identifier\b|\blicen[cs]ed under\b|\ball rights reserved\b`)
+
+// Returns the location of the comment block at the top of the file, if it holds a copyright or license notice
+// Only the comments of the given style are considered, the lines of other styles being most likely code
+// Comments of any of the built-in styles are considered when the style is unknown, i.e. nil
+// The caller is expected to have checked beforehand that this block does not match the configured header
+func DetectForeignHeader(contents string, style CommentStyle) []int {
+	styles := SupportedStyles()
+	if style != nil {
+		styles = []CommentStyle{style}
+	}
+	start, end := leadingComment(contents, styles)
+	if start == end || !noticeRegex.MatchString(contents[start:end]) {
+		return nil
+	}
+	return []int{start, end}
+}
+
+// computes the location of the first comment block, ignoring the blank lines preceding it
+func leadingComment(contents string, styles []CommentStyle) (int, int) {
+	offset := 0
+	start := -1
+	pendingClosing := ""
+	for offset < len(contents) {
+		lineEnd := strings.Index(contents[offset:], "\n") + 1
+		if lineEnd == 0 {
+			lineEnd = len(contents) - offset
+		}
+		line := strings.TrimSpace(contents[offset : offset+lineEnd])
+		switch {
+		case pendingClosing != "":
+			if strings.Contains(line, pendingClosing) {
+				pendingClosing = ""
+			}
+		case line == "":
+			if start != -1 {
+				return start, offset
+			}
+		default:
+			isComment, closing := commentLine(line, styles)
+			if !isComment {
+				if start == -1 {
+					return offset, offset
+				}
+				return start, offset
+			}
+			pendingClosing = closing
+		}
+		if start == -1 && line != "" {
+			start = offset
+		}
+		offset += lineEnd
+	}
+	if start == -1 {
+		return offset, offset
+	}
+	return start, offset
+}
+
+// checks whether the trimmed line is commented, and returns the closing string to look for if it opens a block comment
+func commentLine(line string, styles []CommentStyle) (bool, string) {
+	for _, style := range styles {
+		opening := strings.TrimSpace(style.GetOpeningString())
+		if opening != "" && strings.HasPrefix(line, opening) {
+			closing := strings.TrimSpace(style.GetClosingString())
+			if strings.Contains(line[len(opening):], closing) {
+				return true, ""
+			}
+			return true, closing
+		}
+	}
+	for _, style := range styles {
+		prefix := strings.TrimSpace(style.GetString())
+		if prefix != "" && strings.HasPrefix(line, prefix) {
+			return true, ""
+		}
+	}
+	return false, ""
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core_test
+
+import (
+	"github.com/fbiville/headache/internal/pkg/core"
+	styles "github.com/fbiville/headache/internal/pkg/core/comment_styles"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Foreign header detection", func() {
+
+	It("detects copyright notices in the leading block comment", func() {
+		contents := "\n/*\n   Copyright 2010 Someone Else\n */\npackage foo\n"
+
+		location := core.DetectForeignHeader(contents, styles.SlashStar{})
+
+		Expect(location).NotTo(BeNil())
+		Expect(contents[location[0]:location[1]]).To(Equal("/*\n   Copyright 2010 Someone Else\n */\n"))
+	})
+
+	It("detects license notices in the leading line comments", func() {
+		contents := "# SPDX-License-Identifier: BSD-3-Clause\n# some more text\n\nimport os\n"
+
+		location := core.DetectForeignHeader(contents, styles.Hash{})
+
+		Expect(location).NotTo(BeNil())
+		Expect(contents[location[0]:location[1]]).To(Equal("# SPDX-License-Identifier: BSD-3-Clause\n# some more text\n"))
+	})
+
+	It("ignores leading comments without any notice", func() {
+		Expect(core.DetectForeignHeader("// Package foo does things\npackage foo\n", styles.SlashSlash{})).To(BeNil())
+	})
+
+	It("ignores notices that are not at the top of the file", func() {
+		Expect(core.DetectForeignHeader("package foo\n\n// Copyright 2010 Someone Else\n", styles.SlashSlash{})).To(BeNil())
+	})
+
+	It("ignores parenthesized c in comments", func() {
+		Expect(core.DetectForeignHeader("// returns f(c) for the copyrighted\n// character c\npackage foo\n", styles.SlashSlash{})).To(BeNil())
+	})
+
+	It("detects standalone (c) notices", func() {
+		contents := "// (c) 2010 Someone Else\npackage foo\n"
+
+		Expect(core.DetectForeignHeader(contents, styles.SlashSlash{})).To(Equal([]int{0, 25}))
+	})
+
+	It("ignores the lines that are not comments in the style of the file", func() {
+		contents := "#include <stdio.h> /* Copyright 2010 Someone Else */\nint main() {}\n"
+
+		Expect(core.DetectForeignHeader(contents, styles.SlashStar{})).To(BeNil())
+	})
+
+	It("considers the comments of all the built-in styles when the style of the file is unknown", func() {
+		contents := "# Copyright 2010 Someone Else\nimport os\n"
+
+		Expect(core.DetectForeignHeader(contents, nil)).To(Equal([]int{0, 30}))
+	})
+})
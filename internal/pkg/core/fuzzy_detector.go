@@ -0,0 +1,181 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"regexp"
+	"strings"
+	tpl "text/template"
+)
+
+var (
+	wordRegex    = regexp.MustCompile(`[\pL\pN]+`)
+	numberRegex  = regexp.MustCompile(`^\pN+$`)
+	commentRegex = regexp.MustCompile(`^[^\pL\pN]*`)
+	// matches the lines stating the copyright holder
+	copyrightLineRegex = regexp.MustCompile(`(?i)\bcopyright\b|\(c\)|©`)
+)
+
+// Detects headers that have been reflowed or slightly reworded, by comparing the words of the leading comment block
+// of files with the words of the header templates
+type FuzzyDetector struct {
+	// minimum similarity, between 0 and 1, for the leading comment block to be considered as a header
+	Threshold float64
+	templates []fuzzyTemplate
+	// comment styles the leading comment block may be written in
+	styles []CommentStyle
+}
+
+type fuzzyTemplate struct {
+	words []string
+	// words of the copyright line and of the data values, such as the owner, the leading comment block must hold to be
+	// considered as a header
+	requiredWords []string
+}
+
+func NewFuzzyDetector(threshold float64, versionedHeader *VersionedHeaderTemplate) (*FuzzyDetector, error) {
+	templates := append([]*HeaderTemplate{versionedHeader.Previous, versionedHeader.Current}, versionedHeader.Legacy...)
+	detector := &FuzzyDetector{Threshold: threshold, styles: detectionStyles(versionedHeader)}
+	for _, template := range templates {
+		text, err := executeWithoutYears(template)
+		if err != nil {
+			return nil, err
+		}
+		words := normalizedWords(text)
+		detector.templates = append(detector.templates, fuzzyTemplate{words: words, requiredWords: requiredWords(template, text)})
+	}
+	return detector, nil
+}
+
+// Returns the location of the leading comment block and its similarity with the closest header template,
+// or nil if the similarity is below the threshold
+// Only the templates whose copyright line and data values are found in the block are considered, so that the same
+// license with another owner is not mistaken for the header
+func (detector *FuzzyDetector) Detect(contents string) ([]int, float64) {
+	start, end := leadingComment(contents, detector.styles)
+	if start == end {
+		return nil, 0
+	}
+	words := normalizedWords(contents[start:end])
+	bestScore := 0.0
+	for _, template := range detector.templates {
+		if !containsAll(words, template.requiredWords) {
+			continue
+		}
+		if score := similarity(words, template.words); score > bestScore {
+			bestScore = score
+		}
+	}
+	if bestScore < detector.Threshold {
+		return nil, bestScore
+	}
+	return []int{start, end}, bestScore
+}
+
+// executes the first pass of the template, with the reserved parameters left empty
+func executeWithoutYears(header *HeaderTemplate) (string, error) {
+	data := make(map[string]string, len(header.Data))
+	for key, value := range header.Data {
+		data[key] = value
+	}
+	for key := range injectReservedParameters(map[string]string{}) {
+		data[key] = ""
+	}
+	// the current time varies from one execution to the next, it is left empty as well
+	functions := tpl.FuncMap{"now": func(string) string { return "" }}
+	template, err := tpl.New("header-words").Funcs(templateFunctions()).Funcs(fileFunctions("")).Funcs(functions).Option("missingkey=zero").Parse(strings.Join(header.Lines, "\n"))
+	if err != nil {
+		return "", err
+	}
+	builder := &strings.Builder{}
+	if err := template.Execute(builder, data); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// returns the words of the copyright lines of the executed template, and of the data values it holds, leaving out
+// the reserved parameters
+func requiredWords(header *HeaderTemplate, text string) []string {
+	result := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		if copyrightLineRegex.MatchString(line) {
+			result = append(result, normalizedWords(line)...)
+		}
+	}
+	templateWords := normalizedWords(text)
+	for key, value := range header.Data {
+		if isReservedParameter(key) {
+			continue
+		}
+		for _, word := range normalizedWords(value) {
+			if containsAll(templateWords, []string{word}) {
+				result = append(result, word)
+			}
+		}
+	}
+	return result
+}
+
+func containsAll(words []string, expectedWords []string) bool {
+	found := make(map[string]bool, len(words))
+	for _, word := range words {
+		found[word] = true
+	}
+	for _, word := range expectedWords {
+		if !found[word] {
+			return false
+		}
+	}
+	return true
+}
+
+// lowercases the words of the text, leaving out comment symbols and numbers such as years
+func normalizedWords(text string) []string {
+	result := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		for _, word := range wordRegex.FindAllString(line[len(commentRegex.FindString(line)):], -1) {
+			if !numberRegex.MatchString(word) {
+				result = append(result, strings.ToLower(word))
+			}
+		}
+	}
+	return result
+}
+
+// computes the similarity of the word sequences, based on the length of their longest common subsequence
+func similarity(words1 []string, words2 []string) float64 {
+	if len(words1)+len(words2) == 0 {
+		return 0
+	}
+	previousRow := make([]int, len(words2)+1)
+	currentRow := make([]int, len(words2)+1)
+	for _, word1 := range words1 {
+		for j, word2 := range words2 {
+			switch {
+			case word1 == word2:
+				currentRow[j+1] = previousRow[j] + 1
+			case previousRow[j+1] > currentRow[j]:
+				currentRow[j+1] = previousRow[j+1]
+			default:
+				currentRow[j+1] = currentRow[j]
+			}
+		}
+		previousRow, currentRow = currentRow, previousRow
+	}
+	return 2 * float64(previousRow[len(words2)]) / float64(len(words1)+len(words2))
+}
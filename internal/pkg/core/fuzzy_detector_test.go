@@ -0,0 +1,86 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core_test
+
+import (
+	"github.com/fbiville/headache/internal/pkg/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fuzzy header detection", func() {
+
+	var detector *core.FuzzyDetector
+
+	BeforeEach(func() {
+		template := &core.HeaderTemplate{
+			Lines: []string{
+				"Copyright {{.YearRange}} {{.Owner}}",
+				"",
+				"Licensed under the Apache License, Version 2.0 (the \"License\");",
+				"you may not use this file except in compliance with the License.",
+			},
+			Data: map[string]string{"Owner": "ACME"},
+		}
+		var err error
+		detector, err = core.NewFuzzyDetector(0.8, &core.VersionedHeaderTemplate{Current: template, Previous: template})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("detects reflowed headers", func() {
+		contents := "/*\n * Copyright (c) 2015-2018 ACME\n * Licensed under the Apache License, Version 2.0 (the \"License\"); you may\n * not use this file except in compliance with the License.\n */\npackage foo\n"
+
+		location, confidence := detector.Detect(contents)
+
+		Expect(location).NotTo(BeNil())
+		Expect(contents[location[0]:location[1]]).To(HavePrefix("/*\n * Copyright (c) 2015-2018 ACME"))
+		Expect(contents[location[1]:]).To(Equal("package foo\n"))
+		Expect(confidence).To(BeNumerically(">", 0.9))
+	})
+
+	It("detects slightly reworded headers", func() {
+		contents := "// Copyright 2015 ACME\n// Licensed under the Apache License, Version 2.0;\n// you may only use this file in compliance with the License.\npackage foo\n"
+
+		location, confidence := detector.Detect(contents)
+
+		Expect(location).NotTo(BeNil())
+		Expect(confidence).To(BeNumerically(">=", 0.8))
+		Expect(confidence).To(BeNumerically("<", 1))
+	})
+
+	It("ignores the same header with another owner", func() {
+		contents := "// Copyright 2015 Someone Else\n// Licensed under the Apache License, Version 2.0 (the \"License\");\n// you may not use this file except in compliance with the License.\npackage foo\n"
+
+		location, _ := detector.Detect(contents)
+
+		Expect(location).To(BeNil())
+	})
+
+	It("ignores unrelated leading comments", func() {
+		location, confidence := detector.Detect("// Package foo computes bars\npackage foo\n")
+
+		Expect(location).To(BeNil())
+		Expect(confidence).To(BeNumerically("<", 0.8))
+	})
+
+	It("ignores files without leading comments", func() {
+		location, confidence := detector.Detect("package foo\n")
+
+		Expect(location).To(BeNil())
+		Expect(confidence).To(BeZero())
+	})
+})
@@ -21,65 +21,379 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	tpl "text/template"
 
 	"github.com/fbiville/headache/internal/pkg/fs"
+	"github.com/fbiville/headache/internal/pkg/helper"
 	"github.com/fbiville/headache/internal/pkg/vcs"
 )
 
+// matches year ranges such as "2018-2020" and year lists such as "2018, 2020-2022, 2024"
+// years are whole numbers, so that the digits of longer numbers such as versions or identifiers are not taken as years
+var yearListRegex = regexp.MustCompile(`\b\d{4}(?:\s*-\s*\d{4})?(?:\s*,\s*\d{4}(?:\s*-\s*\d{4})?)*\b`)
+
 type Headache struct {
 	Fs *fs.FileSystem
+	// clock of the {{now}} template function
+	Clock helper.Clock
+}
+
+type HeaderStatus string
+
+const (
+	UpToDateHeader HeaderStatus = "up-to-date"
+	MissingHeader  HeaderStatus = "missing-header"
+	OutdatedHeader HeaderStatus = "outdated-header"
+	// file starting with a copyright or license notice that does not match the configured header
+	ForeignHeader HeaderStatus = "foreign-header"
+	// file starting with several copies of the header
+	DuplicatedHeader HeaderStatus = "duplicated-header"
+	// sidecar file whose source file does not exist anymore
+	OrphanedSidecar HeaderStatus = "orphaned-sidecar"
+	// statuses specific to header removal
+	DetectedHeader HeaderStatus = "detected-header"
+	NoHeader       HeaderStatus = "no-header"
+	// statuses of the files changed by a regular run, once the change is written
+	InsertedHeader HeaderStatus = "inserted-header"
+	UpdatedHeader  HeaderStatus = "updated-header"
+	RemovedSidecar HeaderStatus = "removed-sidecar"
+)
+
+// Describes the header state of a single file
+type FileReport struct {
+	Path   string       `json:"path"`
+	Status HeaderStatus `json:"status"`
+	// 1-based line span of the detected header, or the first line when no header is found
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+	// header the file is expected to start with
+	ExpectedHeader string `json:"expectedHeader"`
+	// similarity of the detected header with the header template, when detected by fuzzy detection
+	Confidence float64 `json:"confidence,omitempty"`
+	// line ending of the file, "\r\n" or "\n"
+	LineEnding string `json:"-"`
+}
+
+func (report FileReport) RequiresChange() bool {
+	switch report.Status {
+	case MissingHeader, OutdatedHeader, DuplicatedHeader, OrphanedSidecar, DetectedHeader:
+		return true
+	default:
+		return false
+	}
+}
+
+// returns the status of the file once its required change is written
+func (report FileReport) changed() FileReport {
+	switch report.Status {
+	case MissingHeader:
+		report.Status = InsertedHeader
+	case OutdatedHeader, DuplicatedHeader:
+		report.Status = UpdatedHeader
+	case OrphanedSidecar:
+		report.Status = RemovedSidecar
+	}
+	return report
+}
+
+// Converts the line endings of the text, such as the expected header, to the line ending of the file
+func (report FileReport) WithLineEnding(text string) string {
+	if report.LineEnding == "" {
+		return text
+	}
+	return withLineEnding(text, report.LineEnding)
+}
+
+// Updates the header of the files and reports their state after the update
+func (headache *Headache) Run(config *ChangeSet) []FileReport {
+	result := make([]FileReport, 0, len(config.Files))
+	for _, file := range config.Files {
+		result = append(result, *headache.UpdateFile(file, config))
+	}
+	for _, file := range config.SidecarFiles {
+		report, newContents := headache.computeSidecar(file, config)
+		if report.RequiresChange() {
+			headache.writeSidecar(report.Path, newContents)
+		}
+		result = append(result, report.changed())
+	}
+	for _, path := range config.OrphanedSidecars {
+		if err := headache.Fs.FileWriter.Remove(path); err != nil {
+			log.Fatalf("headache execution error, cannot remove orphaned sidecar %s\n\t%v", path, err)
+		}
+		result = append(result, orphanedSidecarReport(path).changed())
+	}
+	return result
+}
+
+// Reports the header state of the files without modifying them
+func (headache *Headache) Check(config *ChangeSet) []FileReport {
+	result := make([]FileReport, 0, len(config.Files))
+	for _, file := range config.Files {
+		report, _ := headache.computeFile(file, config)
+		result = append(result, *report)
+	}
+	for _, file := range config.SidecarFiles {
+		report, _ := headache.computeSidecar(file, config)
+		result = append(result, *report)
+	}
+	for _, path := range config.OrphanedSidecars {
+		result = append(result, orphanedSidecarReport(path))
+	}
+	return result
+}
+
+// Updates the header of the file and reports its state after the update
+func (headache *Headache) UpdateFile(change vcs.FileChange, config *ChangeSet) *FileReport {
+	report, newContents := headache.computeFile(change, config)
+	if report.Status != ForeignHeader {
+		headache.writeToFile(change.Path, newContents)
+	}
+	result := report.changed()
+	return &result
 }
 
-func (headache *Headache) Run(config *ChangeSet) {
-	currentHeaderDetectionRegex := config.HeaderRegex
-	newHeaderTemplate := config.HeaderContents
+// Removes the detected header of the files, unless dry run is enabled, and reports their state prior to the removal
+func (headache *Headache) Remove(config *ChangeSet, dryRun bool) []FileReport {
+	result := make([]FileReport, 0, len(config.Files))
 	for _, file := range config.Files {
-		headache.UpdateFile(file, currentHeaderDetectionRegex, newHeaderTemplate)
+		path := file.Path
+		bytes, err := headache.Fs.FileReader.Read(path)
+		if err != nil {
+			log.Fatalf("headache execution error, cannot read file %s\n\t%v", path, err)
+		}
+		preamble, fileContents := splitPreamble(string(bytes), config.PreambleOf(path))
+		preambleLines := strings.Count(preamble, "\n")
+		report := FileReport{Path: path, Status: NoHeader, StartLine: preambleLines + 1, EndLine: preambleLines + 1}
+		// someone else's header is never removed
+		if matchLocation, _, confidence := config.locateHeader(fileContents); matchLocation != nil {
+			report.Status = DetectedHeader
+			report.Confidence = confidence
+			report.StartLine, report.EndLine = lineSpan(fileContents, matchLocation, preambleLines)
+			if !dryRun {
+				start, end := trimNewlines(fileContents, matchLocation)
+				// blank lines separating the header from the code are dropped as well
+				newContents := preamble + fileContents[:start] + strings.TrimLeft(fileContents[end:], "\r\n")
+				headache.writeToFile(path, []byte(newContents))
+			}
+		}
+		result = append(result, report)
+	}
+	return result
+}
+
+// locates the header with the detection regex, falling back to fuzzy detection when enabled
+// a header matching the detection regex with someone else's data, such as another owner, is returned as foreign
+func (changeSet *ChangeSet) locateHeader(contents string) (matchLocation []int, foreignLocation []int, confidence float64) {
+	matchLocation = changeSet.HeaderRegex.FindStringIndex(contents)
+	if matchLocation != nil && !changeSet.matchesData(contents[matchLocation[0]:matchLocation[1]]) {
+		return nil, matchLocation, 0
+	}
+	if matchLocation == nil && changeSet.FuzzyDetector != nil {
+		matchLocation, confidence = changeSet.FuzzyDetector.Detect(contents)
+		if matchLocation == nil {
+			confidence = 0
+		}
 	}
+	return matchLocation, nil, confidence
 }
 
-func (headache *Headache) UpdateFile(change vcs.FileChange, currentHeaderDetectionRegex *regexp.Regexp, newHeaderTemplate string) {
+func (headache *Headache) computeFile(change vcs.FileChange, config *ChangeSet) (*FileReport, []byte) {
 	path := change.Path
+	newHeaderTemplate := config.HeaderContentsOf(path)
 	bytes, err := headache.Fs.FileReader.Read(path)
 	if err != nil {
 		log.Fatalf("headache execution error, cannot read file %s\n\t%v", path, err)
 	}
 
-	fileContents := string(bytes)
-	matchLocation := currentHeaderDetectionRegex.FindStringIndex(fileContents)
+	// the header is rendered with the line ending of the file
+	ending := lineEnding(string(bytes))
+	// the preamble stays above the header, the header is searched for in the rest of the file
+	preamble, fileContents := splitPreamble(string(bytes), config.PreambleOf(path))
+	preambleLines := strings.Count(preamble, "\n")
+	report := &FileReport{Path: path, Status: MissingHeader, StartLine: preambleLines + 1, EndLine: preambleLines + 1, LineEnding: ending}
+	matchLocation, foreignLocation, confidence := config.locateHeader(fileContents)
+	report.Confidence = confidence
 	existingHeader := ""
 	if matchLocation != nil {
-		existingHeader = fileContents[matchLocation[0]:matchLocation[1]]
-		fileContents = strings.TrimLeft(fileContents[:matchLocation[0]]+fileContents[matchLocation[1]:], "\n")
+		report.Status = OutdatedHeader
+		headerCopies := []string{fileContents[matchLocation[0]:matchLocation[1]]}
+		if config.Repair {
+			matchLocation, headerCopies = extendToDuplicates(fileContents, matchLocation, config.HeaderRegex, config.CurrentHeaderRegex)
+		}
+		if len(headerCopies) > 1 {
+			report.Status = DuplicatedHeader
+		}
+		report.StartLine, report.EndLine = lineSpan(fileContents, matchLocation, preambleLines)
+		existingHeader = earliestHeader(&change, headerCopies)
+		fileContents = strings.TrimLeft(fileContents[:matchLocation[0]]+fileContents[matchLocation[1]:], "\r\n")
+	} else {
+		if foreignLocation == nil {
+			foreignLocation = DetectForeignHeader(fileContents, config.CommentStyleOf(path))
+		}
+		if foreignLocation != nil && !config.PrependToForeignHeaders {
+			// someone else's notice is left untouched
+			report.Status = ForeignHeader
+			report.StartLine, report.EndLine = lineSpan(fileContents, foreignLocation, preambleLines)
+			return report, bytes
+		}
 	}
 
-	finalHeaderContent, err := insertYears(newHeaderTemplate, &change, existingHeader)
+	fileData := headache.fileParameters(newHeaderTemplate, &change, fileContents, config.AuthorOptions)
+	finalHeaderContent, err := insertYears(newHeaderTemplate, &change, existingHeader, fileData, headache.Clock)
 	if err != nil {
 		log.Fatalf("headache execution error, cannot parse header for file %s\n\t%v", path, err)
 	}
-	newContents := append([]byte(fmt.Sprintf("%s%s", finalHeaderContent, "\n\n")), []byte(fileContents)...)
-	headache.writeToFile(path, newContents)
+	if style, maxLineLength := config.CommentStyleOf(path), config.MaxLineLengthOf(path); style != nil && maxLineLength > 0 {
+		finalHeaderContent = WrapComments(finalHeaderContent, style, maxLineLength)
+	}
+	report.ExpectedHeader = finalHeaderContent
+	if preambleLines > 0 {
+		fileContents = strings.TrimLeft(fileContents, "\r\n")
+	}
+	newContents := []byte(fmt.Sprintf("%s%s%s", preamble, withLineEnding(finalHeaderContent+"\n\n", ending), fileContents))
+	if string(newContents) == string(bytes) {
+		report.Status = UpToDateHeader
+	}
+	return report, newContents
 }
 
-func insertYears(template string, change *vcs.FileChange, existingHeader string) (string, error) {
-	t, err := tpl.New("header-second-pass").Parse(template)
+// computes the contents of the sidecar file of the given file, with years computed from the history of the latter
+func (headache *Headache) computeSidecar(change vcs.FileChange, config *ChangeSet) (*FileReport, string) {
+	sidecarTemplate := config.SidecarContents
+	path := SidecarPath(change.Path)
+	report := &FileReport{Path: path, Status: MissingHeader, StartLine: 1, EndLine: 1}
+	existingContents := ""
+	if headache.Fs.IsFile(path) {
+		bytes, err := headache.Fs.FileReader.Read(path)
+		if err != nil {
+			log.Fatalf("headache execution error, cannot read sidecar %s\n\t%v", path, err)
+		}
+		existingContents = string(bytes)
+		report.Status = OutdatedHeader
+		report.EndLine = strings.Count(strings.TrimRight(existingContents, "\n"), "\n") + 1
+	}
+
+	fileData := headache.fileParameters(sidecarTemplate, &change, "", config.AuthorOptions)
+	finalContents, err := insertYears(sidecarTemplate, &change, existingContents, fileData, headache.Clock)
+	if err != nil {
+		log.Fatalf("headache execution error, cannot parse header for sidecar %s\n\t%v", path, err)
+	}
+	report.ExpectedHeader = finalContents
+	report.LineEnding = lineEnding(existingContents)
+	newContents := withLineEnding(finalContents+"\n", report.LineEnding)
+	if newContents == existingContents {
+		report.Status = UpToDateHeader
+	}
+	return report, newContents
+}
+
+func orphanedSidecarReport(path string) FileReport {
+	return FileReport{Path: path, Status: OrphanedSidecar, StartLine: 1, EndLine: 1}
+}
+
+// extends the header match location to the copies of the previous or current header stacked right after it
+func extendToDuplicates(contents string, matchLocation []int, regexes ...*regexp.Regexp) ([]int, []string) {
+	copies := []string{contents[matchLocation[0]:matchLocation[1]]}
+	end := matchLocation[1]
+	for {
+		copyLocation := leadingMatch(contents[end:], regexes)
+		if copyLocation == nil {
+			return []int{matchLocation[0], end}, copies
+		}
+		copies = append(copies, contents[end+copyLocation[0]:end+copyLocation[1]])
+		end += copyLocation[1]
+	}
+}
+
+// finds the first regex match only preceded by blank characters
+func leadingMatch(contents string, regexes []*regexp.Regexp) []int {
+	for _, regex := range regexes {
+		if regex == nil {
+			continue
+		}
+		location := regex.FindStringIndex(contents)
+		if location != nil && location[1] > location[0] && strings.TrimSpace(contents[:location[0]]) == "" {
+			return location
+		}
+	}
+	return nil
+}
+
+// selects the header copy with the earliest start year
+func earliestHeader(change *vcs.FileChange, headers []string) string {
+	result := headers[0]
+	earliestYear, _, _ := ComputeCopyrightYears(change, result)
+	for _, header := range headers[1:] {
+		if startYear, _, err := ComputeCopyrightYears(change, header); err == nil && startYear < earliestYear {
+			result, earliestYear = header, startYear
+		}
+	}
+	return result
+}
+
+// computes the 1-based line span of the match, ignoring its surrounding blank lines
+// the contents are preceded by the given number of lines
+func lineSpan(contents string, matchLocation []int, lineOffset int) (int, int) {
+	start, end := trimNewlines(contents, matchLocation)
+	return lineOffset + strings.Count(contents[:start], "\n") + 1, lineOffset + strings.Count(contents[:end], "\n") + 1
+}
+
+// narrows the match location down by excluding its leading and trailing newlines
+func trimNewlines(contents string, matchLocation []int) (int, int) {
+	start, end := matchLocation[0], matchLocation[1]
+	for start < end && (contents[start] == '\n' || contents[start] == '\r') {
+		start++
+	}
+	for end > start && (contents[end-1] == '\n' || contents[end-1] == '\r') {
+		end--
+	}
+	return start, end
+}
+
+// returns the line ending of the first line of the contents, either \r\n or \n (the default)
+func lineEnding(contents string) string {
+	if index := strings.Index(contents, "\n"); index > 0 && contents[index-1] == '\r' {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// converts the line endings of the contents to the given one
+func withLineEnding(contents string, ending string) string {
+	return strings.Replace(strings.Replace(contents, "\r\n", "\n", -1), "\n", ending, -1)
+}
+
+// executes the second pass of the template with the copyright years and the reserved parameters of the file
+func insertYears(template string, change *vcs.FileChange, existingHeader string, fileData map[string]string, clock helper.Clock) (string, error) {
+	t, err := tpl.New("header-second-pass").Funcs(templateFunctions()).Funcs(fileFunctions(change.Path)).Funcs(clockFunctions(clock)).Parse(template)
 	if err != nil {
 		return "", err
 	}
-	data := make(map[string]string)
+	data := make(map[string]string, len(fileData))
+	for key, value := range fileData {
+		data[key] = value
+	}
 	startYear, endYear, err := ComputeCopyrightYears(change, existingHeader)
 	if err != nil {
 		return "", err
 	}
-	data["YearRange"] = strconv.Itoa(startYear)
+	years, err := ComputeCopyrightYearList(change, existingHeader)
+	if err != nil {
+		return "", err
+	}
+	data["YearRange"] = yearRange(startYear, endYear)
+	data["Year"] = data["YearRange"]
+	data["HeaderDetected"] = ""
+	if existingHeader != "" {
+		data["HeaderDetected"] = "true"
+	}
 	data["StartYear"] = strconv.Itoa(startYear)
 	data["EndYear"] = strconv.Itoa(endYear)
-	if startYear != endYear {
-		data["YearRange"] = fmt.Sprintf("%d-%d", startYear, endYear)
-	}
+	data["YearList"] = yearList(years)
 	builder := &strings.Builder{}
 	err = t.Execute(builder, data)
 	if err != nil {
@@ -88,20 +402,80 @@ func insertYears(template string, change *vcs.FileChange, existingHeader string)
 	return builder.String(), nil
 }
 
-// visible for testing
-func ComputeCopyrightYears(change *vcs.FileChange, existingHeader string) (int, int, error) {
-	regex := regexp.MustCompile(`(\d{4})(?:\s*-\s*(\d{4}))?`)
-	matches := regex.FindStringSubmatch(existingHeader)
-	creationYear := change.CreationYear
-	if len(matches) > 2 {
-		startYearInHeader, err := strconv.Atoi(matches[1])
+// formats the years as a single year if they are the same, as an interval otherwise
+func yearRange(startYear int, endYear int) string {
+	if startYear == endYear {
+		return strconv.Itoa(startYear)
+	}
+	return fmt.Sprintf("%d-%d", startYear, endYear)
+}
+
+// formats the sorted years as single years and intervals of consecutive years, e.g. "2018, 2020-2022, 2024"
+func yearList(years []int) string {
+	intervals := make([]string, 0)
+	for i := 0; i < len(years); {
+		j := i
+		for j+1 < len(years) && years[j+1] == years[j]+1 {
+			j++
+		}
+		intervals = append(intervals, yearRange(years[i], years[j]))
+		i = j + 1
+	}
+	return strings.Join(intervals, ", ")
+}
+
+// parses the first list of years found in the header, the list being formatted as a year range or a year list
+// reversed ranges such as "2020-2018" are read as the range of the same years, the result holds distinct years
+func parseYearList(header string) ([]int, error) {
+	list := yearListRegex.FindString(header)
+	if list == "" {
+		return nil, nil
+	}
+	result := make([]int, 0)
+	for _, interval := range strings.Split(list, ",") {
+		bounds := strings.Split(interval, "-")
+		startYear, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
 		if err != nil {
-			return 0, 0, err
+			return nil, err
+		}
+		endYear := startYear
+		if len(bounds) > 1 {
+			if endYear, err = strconv.Atoi(strings.TrimSpace(bounds[1])); err != nil {
+				return nil, err
+			}
 		}
-		if startYearInHeader < creationYear {
-			creationYear = startYearInHeader
+		if endYear < startYear {
+			startYear, endYear = endYear, startYear
+		}
+		for year := startYear; year <= endYear; year++ {
+			result = append(result, year)
+		}
+	}
+	sort.Ints(result)
+	return distinctYears(result), nil
+}
+
+// removes the duplicates of the sorted years
+func distinctYears(years []int) []int {
+	result := make([]int, 0, len(years))
+	for i, year := range years {
+		if i == 0 || year != years[i-1] {
+			result = append(result, year)
 		}
 	}
+	return result
+}
+
+// visible for testing
+func ComputeCopyrightYears(change *vcs.FileChange, existingHeader string) (int, int, error) {
+	yearsInHeader, err := parseYearList(existingHeader)
+	if err != nil {
+		return 0, 0, err
+	}
+	creationYear := change.CreationYear
+	if len(yearsInHeader) > 0 && yearsInHeader[0] < creationYear {
+		creationYear = yearsInHeader[0]
+	}
 	lastEditionYear := change.LastEditionYear
 	if lastEditionYear != 0 && lastEditionYear != creationYear {
 		return creationYear, lastEditionYear, nil
@@ -109,6 +483,40 @@ func ComputeCopyrightYears(change *vcs.FileChange, existingHeader string) (int,
 	return creationYear, creationYear, nil
 }
 
+// computes the distinct years of the file commits, along with the years of the existing header preceding them
+// visible for testing
+func ComputeCopyrightYearList(change *vcs.FileChange, existingHeader string) ([]int, error) {
+	commitYears := change.Years
+	if len(commitYears) == 0 {
+		startYear, endYear, err := ComputeCopyrightYears(change, "")
+		if err != nil {
+			return nil, err
+		}
+		commitYears = []int{startYear}
+		if endYear != startYear {
+			commitYears = append(commitYears, endYear)
+		}
+	}
+	yearsInHeader, err := parseYearList(existingHeader)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int, 0, len(yearsInHeader)+len(commitYears))
+	for _, year := range yearsInHeader {
+		// the claim predates the history of the file
+		if year < commitYears[0] {
+			result = append(result, year)
+		}
+	}
+	return append(result, commitYears...), nil
+}
+
+func (headache *Headache) writeSidecar(path string, contents string) {
+	if err := headache.Fs.FileWriter.Write(path, contents, 0644); err != nil {
+		log.Fatalf("headache execution error, cannot write sidecar %s\n\t%v", path, err)
+	}
+}
+
 func (headache *Headache) writeToFile(path string, newContents []byte) {
 	file, err := headache.Fs.FileWriter.Open(path, os.O_WRONLY|os.O_TRUNC, os.ModeAppend)
 	if err != nil {
@@ -18,13 +18,16 @@ package core_test
 
 import (
 	"github.com/fbiville/headache/internal/pkg/core"
+	styles "github.com/fbiville/headache/internal/pkg/core/comment_styles"
 	"github.com/fbiville/headache/internal/pkg/fs"
 	"github.com/fbiville/headache/internal/pkg/fs_mocks"
+	"github.com/fbiville/headache/internal/pkg/helper_mocks"
 	"github.com/fbiville/headache/internal/pkg/vcs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"os"
 	"regexp"
+	"time"
 )
 
 var _ = Describe("Headache", func() {
@@ -77,6 +80,32 @@ var _ = Describe("Headache", func() {
 		headache.Run(&configuration)
 	})
 
+	It("writes the header in the comment style of the file extension", func() {
+		header := "# some multi-line header\n# with some text"
+		fakeFile := new(fs_mocks.File)
+		fileContents := "echo hello"
+		fileName := "scripts/some-file.sh"
+		fileReader.On("Read", fileName).
+			Return([]byte(fileContents), nil).
+			Once()
+		fileWriter.On("Open", fileName, os.O_WRONLY|os.O_TRUNC, os.ModeAppend).
+			Return(fakeFile, nil).
+			Once()
+		fakeFile.On(
+			"Write",
+			[]byte(header+delimiter+fileContents)).Return(nil).Once()
+		fakeFile.On("Close").Return(nil).Once()
+
+		configuration := core.ChangeSet{
+			HeaderRegex:             getRegex("some multi-line header", "with some text"),
+			HeaderContents:          "// some multi-line header\n// with some text",
+			ExtensionHeaderContents: map[string]string{"sh": header},
+			Files:                   []vcs.FileChange{{Path: fileName}},
+		}
+
+		headache.Run(&configuration)
+	})
+
 	It("updates the header according to the comment style", func() {
 		oldHeader := "// some multi-line header \n// with some text"
 		newHeader := `/*
@@ -290,6 +319,676 @@ var _ = Describe("Headache", func() {
 		headache.Run(&configuration)
 	})
 
+	It("converts full-text headers to their SPDX form", func() {
+		oldHeader := "// Copyright 2016 ACME\n//\n// Licensed under the MIT License"
+		newHeader := "// SPDX-FileCopyrightText: 2016-2022 ACME\n// SPDX-License-Identifier: MIT"
+		fakeFile := new(fs_mocks.File)
+		fileContents := "hello\nworld"
+		fileName := "some-file-1"
+		fileReader.On("Read", fileName).
+			Return([]byte(oldHeader+delimiter+fileContents), nil).
+			Once()
+		fileWriter.On("Open", fileName, os.O_WRONLY|os.O_TRUNC, os.ModeAppend).
+			Return(fakeFile, nil).
+			Once()
+		fakeFile.On(
+			"Write",
+			[]byte(newHeader+delimiter+fileContents)).Return(nil).Once()
+		fakeFile.On("Close").Return(nil).Once()
+		template, err := core.ParseTemplate(&core.VersionedHeaderTemplate{
+			Current: &core.HeaderTemplate{
+				Lines: []string{"SPDX-FileCopyrightText: {{.YearRange}} {{.Owner}}", "SPDX-License-Identifier: MIT"},
+				Data:  map[string]string{"Owner": "ACME"},
+			},
+			Previous: &core.HeaderTemplate{
+				Lines: []string{"Copyright {{.YearRange}} {{.Owner}}", "", "Licensed under the MIT License"},
+				Data:  map[string]string{"Owner": "ACME"},
+			},
+		}, styles.SlashSlash{})
+		Expect(err).NotTo(HaveOccurred())
+
+		configuration := core.ChangeSet{
+			HeaderRegex:    template.DetectionRegex,
+			HeaderContents: template.ActualContent,
+			Files:          []vcs.FileChange{{Path: fileName, CreationYear: 2018, LastEditionYear: 2022}},
+		}
+
+		headache.Run(&configuration)
+	})
+
+	It("formats the current time of the clock in the header", func() {
+		clock := new(helper_mocks.Clock)
+		clock.On("Now").Return(time.Date(2021, time.March, 4, 10, 0, 0, 0, time.UTC))
+		headache.Clock = clock
+		fakeFile := new(fs_mocks.File)
+		fileReader.On("Read", "some-file").
+			Return([]byte("package foo"), nil).
+			Once()
+		fileWriter.On("Open", "some-file", os.O_WRONLY|os.O_TRUNC, os.ModeAppend).
+			Return(fakeFile, nil).
+			Once()
+		fakeFile.On("Write", []byte("// Copyright 2022 ACME, checked on 2021-03-04"+delimiter+"package foo")).Return(nil).Once()
+		fakeFile.On("Close").Return(nil).Once()
+		template := &core.HeaderTemplate{Lines: []string{`Copyright {{.YearRange}} {{.Owner}}, checked on {{now "2006-01-02"}}`}, Data: map[string]string{"Owner": "ACME"}}
+		parsedTemplate, err := core.ParseTemplate(&core.VersionedHeaderTemplate{Current: template, Previous: template}, styles.SlashSlash{})
+		Expect(err).NotTo(HaveOccurred())
+
+		headache.Run(&core.ChangeSet{
+			HeaderRegex:    parsedTemplate.DetectionRegex,
+			HeaderContents: parsedTemplate.ActualContent,
+			Files:          []vcs.FileChange{{Path: "some-file", CreationYear: 2022, LastEditionYear: 2022}},
+		})
+
+		clock.AssertExpectations(t)
+	})
+
+	It("keeps the start year of SPDX headers", func() {
+		change := vcs.FileChange{
+			Path:            "some-file",
+			CreationYear:    2018,
+			LastEditionYear: 2022,
+		}
+		header := "// SPDX-FileCopyrightText: 2016-2019 ACME\n// SPDX-License-Identifier: MIT"
+
+		startYear, endYear, err := core.ComputeCopyrightYears(&change, header)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(startYear).To(Equal(2016))
+		Expect(endYear).To(Equal(2022))
+	})
+
+	It("lists the commit years, keeping the years of the existing header preceding them", func() {
+		fileReader.On("Read", "some-file.go").
+			Return([]byte("// Copyright 2012, 2018 ACME"+delimiter+"package foo"), nil).
+			Once()
+
+		reports := headache.Check(&core.ChangeSet{
+			HeaderRegex:    getRegexWithParams(map[string]string{"YearList": ""}, "Copyright {{.YearList}} ACME"),
+			HeaderContents: "// Copyright {{.YearList}} ACME",
+			Files: []vcs.FileChange{{
+				Path:            "some-file.go",
+				CreationYear:    2018,
+				LastEditionYear: 2024,
+				Years:           []int{2018, 2020, 2021, 2022, 2024},
+			}},
+		})
+
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Status).To(Equal(core.OutdatedHeader))
+		Expect(reports[0].ExpectedHeader).To(Equal("// Copyright 2012, 2018, 2020-2022, 2024 ACME"))
+	})
+
+	It("inserts the reserved parameters of each file", func() {
+		fileReader.On("Read", "internal/core/foo.go").
+			Return([]byte("package core\n"), nil).
+			Once()
+		fileReader.On("Stat", "internal/core/go.mod").Return(nil, os.ErrNotExist)
+		fileReader.On("Stat", "internal/go.mod").Return(&fs.FakeFileInfo{FileMode: 0644}, nil)
+		fileReader.On("Read", "internal/go.mod").
+			Return([]byte("module github.com/acme/thing\n\ngo 1.12\n"), nil).
+			Once()
+
+		reports := headache.Check(&core.ChangeSet{
+			HeaderRegex:    getRegex("Copyright ACME"),
+			HeaderContents: "// {{.FilePath}}: {{.FileName}} in {{.Directory}} of {{.Module}} ({{.Package}})",
+			Files:          []vcs.FileChange{{Path: "internal/core/foo.go", CreationYear: 2020, LastEditionYear: 2020}},
+		})
+
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].ExpectedHeader).To(Equal("// internal/core/foo.go: foo.go in core of github.com/acme/thing (core)"))
+	})
+
+	It("inserts the authors of each file", func() {
+		fileReader.On("Read", "some-file.go").
+			Return([]byte("package foo"), nil).
+			Once()
+		change := vcs.FileChange{
+			Path:            "some-file.go",
+			CreationYear:    2018,
+			LastEditionYear: 2022,
+			Authors: []vcs.Author{
+				{Name: "Zoe", Email: "zoe@example.com", Years: []int{2018}, Commits: 1},
+				{Name: "Jane", Email: "jane@acme.com", Years: []int{2019, 2020}, Commits: 2},
+				{Name: "John", Email: "john@ACME.com", Years: []int{2022}, Commits: 2},
+				{Name: "Bob", Email: "bob@example.com", Years: []int{2021}, Commits: 3},
+			},
+		}
+
+		reports := headache.Check(&core.ChangeSet{
+			HeaderRegex:    getRegex("Copyright ACME"),
+			HeaderContents: "// Copyright {{.AuthorsWithYears}}\n// Authors: {{.Authors}}",
+			Files:          []vcs.FileChange{change},
+			AuthorOptions: &core.AuthorOptions{
+				Sort:          core.CommitCountAuthorOrder,
+				Limit:         2,
+				Organizations: map[string]string{"@acme.com": "ACME Corp"},
+			},
+		})
+
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].ExpectedHeader).To(Equal("// Copyright ACME Corp (2019-2020, 2022), Bob (2021) and others\n// Authors: ACME Corp, Bob and others"))
+	})
+
+	It("lists the authors by first commit by default", func() {
+		fileReader.On("Read", "some-file.go").
+			Return([]byte("package foo"), nil).
+			Once()
+		change := vcs.FileChange{
+			Path:            "some-file.go",
+			CreationYear:    2018,
+			LastEditionYear: 2021,
+			Authors: []vcs.Author{
+				{Name: "Zoe", Email: "zoe@example.com", Years: []int{2018}, Commits: 1},
+				{Name: "Bob", Email: "bob@example.com", Years: []int{2021}, Commits: 3},
+			},
+		}
+
+		reports := headache.Check(&core.ChangeSet{
+			HeaderRegex:    getRegex("Copyright ACME"),
+			HeaderContents: "// Copyright {{.Authors}}",
+			Files:          []vcs.FileChange{change},
+		})
+
+		Expect(reports[0].ExpectedHeader).To(Equal("// Copyright Zoe, Bob"))
+	})
+
+	It("applies template functions in the second pass", func() {
+		fileReader.On("Read", "some-file.go").
+			Return([]byte("package foo"), nil).
+			Once()
+
+		reports := headache.Check(&core.ChangeSet{
+			HeaderRegex:    getRegex("Copyright ACME"),
+			HeaderContents: "// {{upper .FileName}} {{.Year}}",
+			Files:          []vcs.FileChange{{Path: "some-file.go", CreationYear: 2020, LastEditionYear: 2020}},
+		})
+
+		Expect(reports[0].ExpectedHeader).To(Equal("// SOME-FILE.GO 2020"))
+	})
+
+	It("inserts conditional sections according to the file attributes", func() {
+		fileReader.On("Read", "third_party/lib.go").
+			Return([]byte("package lib"), nil).
+			Once()
+		fileReader.On("Read", "main.py").
+			Return([]byte("# Copyright 2019 ACME"+delimiter+"import os"), nil).
+			Once()
+
+		reports := headache.Check(&core.ChangeSet{
+			HeaderRegex: getRegexWithParams(map[string]string{"Year": ""}, "Copyright {{.Year}} ACME"),
+			HeaderContents: "# Copyright {{.YearRange}} ACME\n" +
+				"{{- if matches \"third_party/**\"}}\n" +
+				"# Portions copyright Someone\n" +
+				"{{- end}}\n" +
+				"# Written in {{if eq .Extension \"go\"}}Go{{else}}Python{{end}}{{if .HeaderDetected}}, updated{{end}}",
+			Files: []vcs.FileChange{
+				{Path: "third_party/lib.go", CreationYear: 2020, LastEditionYear: 2020},
+				{Path: "main.py", CreationYear: 2020, LastEditionYear: 2020},
+			},
+		})
+
+		Expect(reports).To(HaveLen(2))
+		Expect(reports[0].ExpectedHeader).To(Equal("# Copyright 2020 ACME\n# Portions copyright Someone\n# Written in Go"))
+		Expect(reports[1].ExpectedHeader).To(Equal("# Copyright 2019-2020 ACME\n# Written in Python, updated"))
+	})
+
+	It("wraps the header lines longer than the maximum line length", func() {
+		fileReader.On("Read", "some-file.py").
+			Return([]byte("import os"), nil).
+			Once()
+
+		reports := headache.Check(&core.ChangeSet{
+			HeaderRegex:    getRegex("Copyright ACME"),
+			HeaderContents: "# Copyright {{.YearRange}} {{.Authors}}",
+			Files: []vcs.FileChange{{
+				Path:            "some-file.py",
+				CreationYear:    2020,
+				LastEditionYear: 2020,
+				Authors:         []vcs.Author{{Name: "Jane Doe"}, {Name: "John Smith"}, {Name: "Someone Else"}},
+			}},
+			CommentStyle:  core.ParseCommentStyle("Hash"),
+			MaxLineLength: 30,
+		})
+
+		Expect(reports[0].ExpectedHeader).To(Equal("# Copyright 2020 Jane Doe,\n# John Smith, Someone Else"))
+	})
+
+	It("wraps the header lines at the maximum length of the file extension, then of the comment style", func() {
+		fileReader.On("Read", "some-file.py").Return([]byte("import os"), nil).Once()
+		fileReader.On("Read", "some-file.sh").Return([]byte("echo hello"), nil).Once()
+		fileReader.On("Read", "some-file.go").Return([]byte("package foo"), nil).Once()
+
+		reports := headache.Check(&core.ChangeSet{
+			HeaderRegex:    getRegex("Copyright ACME"),
+			HeaderContents: "// Copyright {{.YearRange}} ACME Corporation and its many contributors",
+			ExtensionHeaderContents: map[string]string{
+				"py": "# Copyright {{.YearRange}} ACME Corporation and its many contributors",
+				"sh": "# Copyright {{.YearRange}} ACME Corporation and its many contributors",
+			},
+			Files: []vcs.FileChange{
+				{Path: "some-file.py", CreationYear: 2020, LastEditionYear: 2020},
+				{Path: "some-file.sh", CreationYear: 2020, LastEditionYear: 2020},
+				{Path: "some-file.go", CreationYear: 2020, LastEditionYear: 2020},
+			},
+			CommentStyle:            core.ParseCommentStyle("SlashSlash"),
+			ExtensionCommentStyles:  map[string]core.CommentStyle{"py": core.ParseCommentStyle("Hash"), "sh": core.ParseCommentStyle("Hash")},
+			MaxLineLength:           100,
+			StyleMaxLineLengths:     map[string]int{"hash": 40},
+			ExtensionMaxLineLengths: map[string]int{"sh": 30},
+		})
+
+		Expect(reports[0].ExpectedHeader).To(Equal("# Copyright 2020 ACME Corporation and\n# its many contributors"))
+		Expect(reports[1].ExpectedHeader).To(Equal("# Copyright 2020 ACME\n# Corporation and its many\n# contributors"))
+		Expect(reports[2].ExpectedHeader).To(Equal("// Copyright 2020 ACME Corporation and its many contributors"))
+	})
+
+	It("keeps the preamble lines above the header", func() {
+		header := "# Copyright 2022 ACME"
+		preamble := "#!/usr/bin/env python\n# -*- coding: utf-8 -*-\n"
+		fakeFile := new(fs_mocks.File)
+		fileReader.On("Read", "script.py").
+			Return([]byte(preamble+"\nimport os"), nil).
+			Twice()
+		fileWriter.On("Open", "script.py", os.O_WRONLY|os.O_TRUNC, os.ModeAppend).
+			Return(fakeFile, nil).
+			Once()
+		fakeFile.On("Write", []byte(preamble+header+delimiter+"import os")).Return(nil).Once()
+		fakeFile.On("Close").Return(nil).Once()
+		configuration := core.ChangeSet{
+			HeaderRegex:    getRegexWithParams(map[string]string{"Year": "{{.Year}}"}, "Copyright {{.Year}} ACME"),
+			HeaderContents: "# Copyright {{.YearRange}} ACME",
+			Files:          []vcs.FileChange{{Path: "script.py", CreationYear: 2022}},
+			CommentStyle:   core.ParseCommentStyle("Hash"),
+			StylePreambles: map[string][]*regexp.Regexp{
+				"hash": {regexp.MustCompile(`^(?:#!.*)$`), regexp.MustCompile(`^(?:#.*coding[:=].*)$`)},
+			},
+		}
+
+		headache.Run(&configuration)
+		reports := headache.Check(&configuration)
+
+		Expect(reports[0].Status).To(Equal(core.MissingHeader))
+		Expect(reports[0].StartLine).To(Equal(3))
+	})
+
+	It("detects headers below the preamble lines", func() {
+		fileReader.On("Read", "script.sh").
+			Return([]byte("#!/bin/sh\n# Copyright 2022 ACME\n\necho hello"), nil).
+			Once()
+
+		reports := headache.Check(&core.ChangeSet{
+			HeaderRegex:    getRegexWithParams(map[string]string{"Year": "{{.Year}}"}, "Copyright {{.Year}} ACME"),
+			HeaderContents: "# Copyright {{.YearRange}} ACME",
+			Files:          []vcs.FileChange{{Path: "script.sh", CreationYear: 2022}},
+			CommentStyle:   core.ParseCommentStyle("Hash"),
+			StylePreambles: map[string][]*regexp.Regexp{"hash": {regexp.MustCompile(`^(?:#!.*)$`)}},
+		})
+
+		Expect(reports[0].Status).To(Equal(core.UpToDateHeader))
+		Expect(reports[0].StartLine).To(Equal(2))
+		Expect(reports[0].EndLine).To(Equal(2))
+	})
+
+	It("keeps the byte order mark at the beginning of the file", func() {
+		fakeFile := new(fs_mocks.File)
+		fileReader.On("Read", "some-file.go").
+			Return([]byte("\uFEFF// Copyright 2019 ACME\n\nhello"), nil).
+			Once()
+		fileWriter.On("Open", "some-file.go", os.O_WRONLY|os.O_TRUNC, os.ModeAppend).
+			Return(fakeFile, nil).
+			Once()
+		fakeFile.On("Write", []byte("\uFEFF"+"// Copyright 2019-2022 ACME"+delimiter+"hello")).Return(nil).Once()
+		fakeFile.On("Close").Return(nil).Once()
+
+		headache.Run(&core.ChangeSet{
+			HeaderRegex:    getRegexWithParams(map[string]string{"Year": "{{.Year}}"}, "Copyright {{.Year}} ACME"),
+			HeaderContents: "// Copyright {{.YearRange}} ACME",
+			Files:          []vcs.FileChange{{Path: "some-file.go", CreationYear: 2022, LastEditionYear: 2022}},
+		})
+	})
+
+	It("renders the header with the line endings of the file", func() {
+		fakeFile := new(fs_mocks.File)
+		fileReader.On("Read", "outdated.cs").
+			Return([]byte("/*\r\n * Copyright 2019 ACME\r\n */\r\n\r\nclass Foo {}\r\n"), nil).
+			Once()
+		fileReader.On("Read", "up-to-date.cs").
+			Return([]byte("/*\r\n * Copyright 2022 ACME\r\n */\r\n\r\nclass Foo {}\r\n"), nil).
+			Once()
+		fileWriter.On("Open", "outdated.cs", os.O_WRONLY|os.O_TRUNC, os.ModeAppend).
+			Return(fakeFile, nil).
+			Once()
+		fakeFile.On("Write", []byte("/*\r\n * Copyright 2019-2022 ACME\r\n */\r\n\r\nclass Foo {}\r\n")).Return(nil).Once()
+		fakeFile.On("Close").Return(nil).Once()
+		configuration := core.ChangeSet{
+			HeaderRegex:    getRegexWithParams(map[string]string{"Year": "{{.Year}}"}, "Copyright {{.Year}} ACME"),
+			HeaderContents: "/*\n * Copyright {{.YearRange}} ACME\n */",
+			Files:          []vcs.FileChange{{Path: "outdated.cs", CreationYear: 2022, LastEditionYear: 2022}},
+		}
+
+		headache.Run(&configuration)
+		configuration.Files = []vcs.FileChange{{Path: "up-to-date.cs", CreationYear: 2022, LastEditionYear: 2022}}
+		reports := headache.Check(&configuration)
+
+		Expect(reports[0].Status).To(Equal(core.UpToDateHeader))
+		Expect(reports[0].StartLine).To(Equal(1))
+		Expect(reports[0].EndLine).To(Equal(3))
+		Expect(reports[0].LineEnding).To(Equal("\r\n"))
+	})
+
+	It("parses the start year of year lists", func() {
+		change := vcs.FileChange{CreationYear: 2016, LastEditionYear: 2020}
+
+		startYear, endYear, err := core.ComputeCopyrightYears(&change, "# Copyright 2015, 2017-2019 ACME")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(startYear).To(Equal(2015))
+		Expect(endYear).To(Equal(2020))
+	})
+
+	It("only parses whole numbers as years", func() {
+		change := vcs.FileChange{CreationYear: 2016, LastEditionYear: 2020}
+
+		startYear, _, err := core.ComputeCopyrightYears(&change, "# Build 123456, Copyright 2015 ACME")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(startYear).To(Equal(2015))
+	})
+
+	It("normalizes reversed year ranges and overlapping intervals", func() {
+		change := vcs.FileChange{Years: []int{2021, 2022}}
+
+		years, err := core.ComputeCopyrightYearList(&change, "# Copyright 2018-2016, 2017 ACME")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(years).To(Equal([]int{2016, 2017, 2018, 2021, 2022}))
+	})
+
+	It("falls back to the creation and last edition years when commit years are unknown", func() {
+		change := vcs.FileChange{CreationYear: 2016, LastEditionYear: 2020}
+
+		years, err := core.ComputeCopyrightYearList(&change, "# Copyright 2014-2016 ACME")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(years).To(Equal([]int{2014, 2015, 2016, 2020}))
+	})
+
+	It("writes the sidecar files of files that cannot carry comments and removes orphaned sidecars", func() {
+		fileReader.On("Stat", "logo.png.license").Return(nil, os.ErrNotExist)
+		fileReader.On("Stat", "font.ttf.license").Return(&fs.FakeFileInfo{FileMode: 0644}, nil)
+		fileReader.On("Read", "font.ttf.license").
+			Return([]byte("SPDX-FileCopyrightText: 2016 ACME\nSPDX-License-Identifier: MIT\n"), nil)
+		fileReader.On("Stat", "data.json.license").Return(&fs.FakeFileInfo{FileMode: 0644}, nil)
+		fileReader.On("Read", "data.json.license").
+			Return([]byte("SPDX-FileCopyrightText: 2020 ACME\nSPDX-License-Identifier: MIT\n"), nil)
+		fileWriter.On("Write", "logo.png.license",
+			"SPDX-FileCopyrightText: 2019-2022 ACME\nSPDX-License-Identifier: MIT\n", os.FileMode(0644)).
+			Return(nil)
+		fileWriter.On("Write", "font.ttf.license",
+			"SPDX-FileCopyrightText: 2016-2022 ACME\nSPDX-License-Identifier: MIT\n", os.FileMode(0644)).
+			Return(nil)
+		fileWriter.On("Remove", "deleted.png.license").Return(nil)
+
+		reports := headache.Run(&core.ChangeSet{
+			HeaderRegex:     getRegex("Copyright ACME"),
+			SidecarContents: "SPDX-FileCopyrightText: {{.YearRange}} ACME\nSPDX-License-Identifier: MIT",
+			SidecarFiles: []vcs.FileChange{
+				{Path: "logo.png", CreationYear: 2019, LastEditionYear: 2022},
+				{Path: "font.ttf", CreationYear: 2018, LastEditionYear: 2022},
+				{Path: "data.json", CreationYear: 2020, LastEditionYear: 2020},
+			},
+			OrphanedSidecars: []string{"deleted.png.license"},
+		})
+
+		Expect(reports).To(HaveLen(4))
+		Expect(reports[0].Path).To(Equal("logo.png.license"))
+		Expect(reports[0].Status).To(Equal(core.InsertedHeader))
+		Expect(reports[1].Path).To(Equal("font.ttf.license"))
+		Expect(reports[1].Status).To(Equal(core.UpdatedHeader))
+		Expect(reports[2].Path).To(Equal("data.json.license"))
+		Expect(reports[2].Status).To(Equal(core.UpToDateHeader))
+		Expect(reports[3]).To(Equal(core.FileReport{Path: "deleted.png.license", Status: core.RemovedSidecar, StartLine: 1, EndLine: 1}))
+	})
+
+	It("leaves files with a foreign header untouched", func() {
+		foreignHeader := "/*\n * Copyright 2010 Someone Else\n * All rights reserved.\n */"
+		fileReader.On("Read", "vendored.go").
+			Return([]byte(foreignHeader+delimiter+"package foo"), nil).
+			Once()
+
+		reports := headache.Run(&core.ChangeSet{
+			HeaderRegex:    getRegex("Copyright ACME"),
+			HeaderContents: "// Copyright ACME",
+			Files:          []vcs.FileChange{{Path: "vendored.go"}},
+		})
+
+		Expect(reports).To(Equal([]core.FileReport{{Path: "vendored.go", Status: core.ForeignHeader, StartLine: 1, EndLine: 4, LineEnding: "\n"}}))
+	})
+
+	It("leaves files with the header of another owner untouched", func() {
+		template := &core.HeaderTemplate{Lines: []string{"Copyright {{.YearRange}} {{.Owner}}", "Licensed under the Apache License, Version 2.0"}, Data: map[string]string{"Owner": "ACME"}}
+		parsedTemplate, err := core.ParseTemplate(&core.VersionedHeaderTemplate{Current: template, Previous: template}, core.ParseCommentStyle("SlashSlash"))
+		Expect(err).NotTo(HaveOccurred())
+		fileReader.On("Read", "vendored.go").
+			Return([]byte("// Copyright 2010 Someone Else\n// Licensed under the Apache License, Version 2.0"+delimiter+"package foo"), nil).
+			Once()
+
+		reports := headache.Run(&core.ChangeSet{
+			CommentStyle:    core.ParseCommentStyle("SlashSlash"),
+			HeaderRegex:     parsedTemplate.DetectionRegex,
+			DataHeaderRegex: parsedTemplate.DataDetectionRegex,
+			HeaderContents:  parsedTemplate.ActualContent,
+			Files:           []vcs.FileChange{{Path: "vendored.go"}},
+		})
+
+		Expect(reports).To(Equal([]core.FileReport{{Path: "vendored.go", Status: core.ForeignHeader, StartLine: 1, EndLine: 2, LineEnding: "\n"}}))
+	})
+
+	It("prepends the header to foreign headers when enabled", func() {
+		foreignHeader := "/*\n * Copyright 2010 Someone Else\n */"
+		newHeader := "// Copyright ACME"
+		fakeFile := new(fs_mocks.File)
+		fileReader.On("Read", "vendored.go").
+			Return([]byte(foreignHeader+delimiter+"package foo"), nil).
+			Once()
+		fileWriter.On("Open", "vendored.go", os.O_WRONLY|os.O_TRUNC, os.ModeAppend).
+			Return(fakeFile, nil).
+			Once()
+		fakeFile.On("Write", []byte(newHeader+delimiter+foreignHeader+delimiter+"package foo")).Return(nil).Once()
+		fakeFile.On("Close").Return(nil).Once()
+
+		reports := headache.Run(&core.ChangeSet{
+			HeaderRegex:             getRegex("Copyright ACME"),
+			HeaderContents:          newHeader,
+			Files:                   []vcs.FileChange{{Path: "vendored.go"}},
+			PrependToForeignHeaders: true,
+		})
+
+		Expect(reports[0].Status).To(Equal(core.InsertedHeader))
+	})
+
+	It("replaces reflowed headers found by fuzzy detection", func() {
+		template := &core.HeaderTemplate{Lines: []string{"Copyright {{.YearRange}} ACME", "All rights reserved by the ACME corporation"}}
+		detector, err := core.NewFuzzyDetector(0.8, &core.VersionedHeaderTemplate{Current: template, Previous: template})
+		Expect(err).NotTo(HaveOccurred())
+		fileReader.On("Read", "reflowed.go").
+			Return([]byte("// Copyright 2015 ACME All rights\n// reserved by the ACME corporation"+delimiter+"package foo"), nil).
+			Once()
+
+		reports := headache.Check(&core.ChangeSet{
+			HeaderRegex:    getRegex("Copyright ACME", "All rights reserved by the ACME corporation"),
+			HeaderContents: "// Copyright {{.YearRange}} ACME\n// All rights reserved by the ACME corporation",
+			Files:          []vcs.FileChange{{Path: "reflowed.go", CreationYear: 2016, LastEditionYear: 2020}},
+			FuzzyDetector:  detector,
+		})
+
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Status).To(Equal(core.OutdatedHeader))
+		Expect(reports[0].StartLine).To(Equal(1))
+		Expect(reports[0].EndLine).To(Equal(2))
+		Expect(reports[0].ExpectedHeader).To(Equal("// Copyright 2015-2020 ACME\n// All rights reserved by the ACME corporation"))
+		Expect(reports[0].Confidence).To(BeNumerically("==", 1))
+	})
+
+	It("leaves reflowed headers of another owner untouched", func() {
+		template := &core.HeaderTemplate{Lines: []string{"Copyright {{.YearRange}} {{.Owner}}", "Licensed under the Apache License, Version 2.0"}, Data: map[string]string{"Owner": "ACME"}}
+		detector, err := core.NewFuzzyDetector(0.8, &core.VersionedHeaderTemplate{Current: template, Previous: template})
+		Expect(err).NotTo(HaveOccurred())
+		fileReader.On("Read", "vendored.go").
+			Return([]byte("// Copyright 2015 Someone Else Licensed under\n// the Apache License, Version 2.0"+delimiter+"package foo"), nil).
+			Once()
+
+		reports := headache.Check(&core.ChangeSet{
+			CommentStyle:   core.ParseCommentStyle("SlashSlash"),
+			HeaderRegex:    getRegexWithParams(map[string]string{"YearRange": "", "Owner": ""}, "Copyright {{.YearRange}} {{.Owner}}", "Licensed under the Apache License, Version 2.0"),
+			HeaderContents: "// Copyright {{.YearRange}} ACME\n// Licensed under the Apache License, Version 2.0",
+			Files:          []vcs.FileChange{{Path: "vendored.go", CreationYear: 2016, LastEditionYear: 2020}},
+			FuzzyDetector:  detector,
+		})
+
+		Expect(reports).To(Equal([]core.FileReport{{Path: "vendored.go", Status: core.ForeignHeader, StartLine: 1, EndLine: 2, LineEnding: "\n"}}))
+	})
+
+	It("collapses stacked copies of the header in repair mode, keeping the earliest start year", func() {
+		previousHeader := "// Copyright 2016 ACME\n// Some license"
+		currentHeader := "// Copyright 2014-2018 ACME\n// Some other license"
+		newHeader := "// Copyright {{.YearRange}} ACME\n// Some other license"
+		fakeFile := new(fs_mocks.File)
+		fileReader.On("Read", "stacked.go").
+			Return([]byte(previousHeader+delimiter+currentHeader+"\n"+previousHeader+delimiter+"package foo"), nil).
+			Once()
+		fileWriter.On("Open", "stacked.go", os.O_WRONLY|os.O_TRUNC, os.ModeAppend).
+			Return(fakeFile, nil).
+			Once()
+		fakeFile.On("Write", []byte("// Copyright 2014-2022 ACME\n// Some other license"+delimiter+"package foo")).Return(nil).Once()
+		fakeFile.On("Close").Return(nil).Once()
+
+		reports := headache.Run(&core.ChangeSet{
+			HeaderRegex:        getRegexWithParams(map[string]string{"YearRange": ""}, "Copyright {{.YearRange}} ACME", "Some license"),
+			CurrentHeaderRegex: getRegexWithParams(map[string]string{"YearRange": ""}, "Copyright {{.YearRange}} ACME", "Some other license"),
+			HeaderContents:     newHeader,
+			Files:              []vcs.FileChange{{Path: "stacked.go", CreationYear: 2017, LastEditionYear: 2022}},
+			Repair:             true,
+		})
+
+		Expect(reports[0].Status).To(Equal(core.UpdatedHeader))
+		Expect(reports[0].StartLine).To(Equal(1))
+		Expect(reports[0].EndLine).To(Equal(7))
+	})
+
+	It("only replaces the first copy of the header outside of repair mode", func() {
+		header := "// Copyright 2016 ACME"
+		fakeFile := new(fs_mocks.File)
+		fileReader.On("Read", "stacked.go").
+			Return([]byte(header+"\n"+header+delimiter+"package foo"), nil).
+			Once()
+		fileWriter.On("Open", "stacked.go", os.O_WRONLY|os.O_TRUNC, os.ModeAppend).
+			Return(fakeFile, nil).
+			Once()
+		fakeFile.On("Write", []byte("// Copyright 2016-2022 ACME"+delimiter+header+delimiter+"package foo")).Return(nil).Once()
+		fakeFile.On("Close").Return(nil).Once()
+
+		reports := headache.Run(&core.ChangeSet{
+			HeaderRegex:    getRegexWithParams(map[string]string{"YearRange": ""}, "Copyright {{.YearRange}} ACME"),
+			HeaderContents: "// Copyright {{.YearRange}} ACME",
+			Files:          []vcs.FileChange{{Path: "stacked.go", CreationYear: 2017, LastEditionYear: 2022}},
+		})
+
+		Expect(reports[0].Status).To(Equal(core.UpdatedHeader))
+	})
+
+	It("reports missing, outdated and up-to-date headers without writing files", func() {
+		header := "// Copyright 2022 ACME"
+		fileReader.On("Read", "missing.go").
+			Return([]byte("hello\nworld"), nil).
+			Once()
+		fileReader.On("Read", "outdated.go").
+			Return([]byte("package foo\n\n// Copyright 2019 ACME\n\nhello"), nil).
+			Once()
+		fileReader.On("Read", "up-to-date.go").
+			Return([]byte(header+delimiter+"hello"), nil).
+			Once()
+
+		configuration := core.ChangeSet{
+			HeaderRegex:    getRegexWithParams(map[string]string{"Year": "{{.Year}}"}, "Copyright {{.Year}} ACME"),
+			HeaderContents: "// Copyright {{.YearRange}} ACME",
+			Files: []vcs.FileChange{
+				{Path: "missing.go", CreationYear: 2022},
+				{Path: "outdated.go", CreationYear: 2022, LastEditionYear: 2022},
+				{Path: "up-to-date.go", CreationYear: 2022},
+			},
+		}
+
+		reports := headache.Check(&configuration)
+
+		Expect(reports).To(Equal([]core.FileReport{
+			{Path: "missing.go", Status: core.MissingHeader, StartLine: 1, EndLine: 1, ExpectedHeader: header, LineEnding: "\n"},
+			{Path: "outdated.go", Status: core.OutdatedHeader, StartLine: 3, EndLine: 3, ExpectedHeader: "// Copyright 2019-2022 ACME", LineEnding: "\n"},
+			{Path: "up-to-date.go", Status: core.UpToDateHeader, StartLine: 1, EndLine: 1, ExpectedHeader: header, LineEnding: "\n"},
+		}))
+	})
+
+	It("removes detected headers along with the blank lines following them", func() {
+		fakeFile := new(fs_mocks.File)
+		fileReader.On("Read", "with-header.sh").
+			Return([]byte("#!/bin/sh\n\n# Copyright 2019 ACME\n#\n# Some license\n\n\necho hello"), nil).
+			Once()
+		fileReader.On("Read", "without-header.sh").
+			Return([]byte("echo world"), nil).
+			Once()
+		fileWriter.On("Open", "with-header.sh", os.O_WRONLY|os.O_TRUNC, os.ModeAppend).
+			Return(fakeFile, nil).
+			Once()
+		fakeFile.On("Write", []byte("#!/bin/sh\n\necho hello")).Return(nil).Once()
+		fakeFile.On("Close").Return(nil).Once()
+
+		configuration := core.ChangeSet{
+			HeaderRegex: getRegexWithParams(map[string]string{"Year": "{{.Year}}"}, "Copyright {{.Year}} ACME", "", "Some license"),
+			Files:       []vcs.FileChange{{Path: "with-header.sh"}, {Path: "without-header.sh"}},
+		}
+
+		reports := headache.Remove(&configuration, false)
+
+		Expect(reports).To(Equal([]core.FileReport{
+			{Path: "with-header.sh", Status: core.DetectedHeader, StartLine: 3, EndLine: 5},
+			{Path: "without-header.sh", Status: core.NoHeader, StartLine: 1, EndLine: 1},
+		}))
+	})
+
+	It("only reports detected headers to remove in dry-run mode", func() {
+		fileReader.On("Read", "with-header.sh").
+			Return([]byte("# Copyright 2019 ACME\n\necho hello"), nil).
+			Once()
+
+		configuration := core.ChangeSet{
+			HeaderRegex: getRegexWithParams(map[string]string{"Year": "{{.Year}}"}, "Copyright {{.Year}} ACME"),
+			Files:       []vcs.FileChange{{Path: "with-header.sh"}},
+		}
+
+		reports := headache.Remove(&configuration, true)
+
+		Expect(reports).To(Equal([]core.FileReport{
+			{Path: "with-header.sh", Status: core.DetectedHeader, StartLine: 1, EndLine: 1},
+		}))
+	})
+
+	It("does not remove the header of another owner", func() {
+		template := &core.HeaderTemplate{Lines: []string{"Copyright {{.YearRange}} {{.Owner}}", "Licensed under the Apache License, Version 2.0"}, Data: map[string]string{"Owner": "ACME"}}
+		parsedTemplate, err := core.ParseTemplate(&core.VersionedHeaderTemplate{Current: template, Previous: template}, core.ParseCommentStyle("SlashSlash"))
+		Expect(err).NotTo(HaveOccurred())
+		fileReader.On("Read", "vendored.go").
+			Return([]byte("// Copyright 2010 Someone Else\n// Licensed under the Apache License, Version 2.0"+delimiter+"package foo"), nil).
+			Once()
+
+		reports := headache.Remove(&core.ChangeSet{
+			HeaderRegex:     parsedTemplate.DetectionRegex,
+			DataHeaderRegex: parsedTemplate.DataDetectionRegex,
+			Files:           []vcs.FileChange{{Path: "vendored.go"}},
+		}, false)
+
+		Expect(reports).To(Equal([]core.FileReport{{Path: "vendored.go", Status: core.NoHeader, StartLine: 1, EndLine: 1}}))
+	})
+
 	It("replaces single future copyright header date with single commit year", func() {
 		change := vcs.FileChange{
 			Path:            "pkg/fileutils/abs_test.go",
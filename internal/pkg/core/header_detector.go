@@ -20,18 +20,49 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	tpl "text/template"
 )
 
 func ComputeHeaderDetectionRegex(lines []string, data map[string]string) (string, error) {
-	unprocessedRegex := strings.Join(computeRegex(lines), "")
-	processedRegex, err := injectDataRegex(unprocessedRegex, data)
+	return headerDetectionRegex(lines, regexValues(data), SupportedStyles())
+}
+
+// Computes the detection regex of headers whose lines may have been wrapped by WrapComments: words and values may be
+// separated by a line break followed by a comment prefix
+func ComputeWrappedHeaderDetectionRegex(lines []string, data map[string]string) (string, error) {
+	return wrappedHeaderDetectionRegex(lines, regexValues(data), SupportedStyles())
+}
+
+func headerDetectionRegex(lines []string, values map[string]string, styles []CommentStyle) (string, error) {
+	unprocessedRegex := strings.Join(computeRegex(lines, styles), "")
+	processedRegex, err := injectDataRegex(unprocessedRegex, values)
 	return processedRegex, err
 }
 
-func computeRegex(lines []string) []string {
-	styles := extractValues(SupportedStyleCatalog())
+func wrappedHeaderDetectionRegex(lines []string, values map[string]string, styles []CommentStyle) (string, error) {
+	regex, err := headerDetectionRegex(lines, values, styles)
+	if err != nil {
+		return "", err
+	}
+	lineBreak := fmt.Sprintf(`[\t ]*\r?\n[\t ]*%s?[\t ]*`, combineRegexes(sortedStyles(styles), prefixRegex))
+	return strings.NewReplacer(
+		`\E +\Q`, fmt.Sprintf(`\E(?: +|%s)\Q`, lineBreak),
+		`\E.*\Q`, fmt.Sprintf(`\E(?:.*%s)*?.*\Q`, lineBreak),
+	).Replace(regex), nil
+}
+
+var (
+	// conditional sections within a single line, such as {{if .Package}}package {{.Package}}{{end}}
+	inlineConditionalRegex = regexp.MustCompile(`\{\{-?\s*(?:if|range|with)\b.*?\{\{-?\s*end\s*-?\}\}`)
+	// action outputting a wildcard once executed by injectDataRegex
+	wildcardAction = fmt.Sprintf("{{%s}}", strconv.Quote(`\E.*\Q`))
+)
+
+// the header may be commented in any of the given styles
+func computeRegex(lines []string, commentStyles []CommentStyle) []string {
+	styles := sortedStyles(commentStyles)
 	result := make([]string, 0)
 	result = append(result, Flags())
 	result = append(result, OpeningLine(styles))
@@ -39,14 +70,35 @@ func computeRegex(lines []string) []string {
 		if line == "" {
 			continue
 		}
+		// lines of conditional sections are optional
+		if isControlLine(line) {
+			switch controlKeyword(strings.TrimSpace(line)) {
+			case "else":
+				result = append(result, ")?(?:")
+			case "end":
+				result = append(result, ")?")
+			default:
+				result = append(result, "(?:")
+			}
+			continue
+		}
 		result = append(result, commentedEmptyLine(styles))
-		result = append(result, MatchingLine(line, styles))
+		result = append(result, MatchingLine(inlineConditionalRegex.ReplaceAllString(line, wildcardAction), styles))
 	}
 	result = append(result, commentedEmptyLine(styles))
 	result = append(result, ClosingLine(styles))
 	return result
 }
 
+// combines detection regexes into one matching any of them, the first one taking precedence
+func alternateRegexes(regexes ...string) string {
+	alternatives := make([]string, len(regexes))
+	for i, regex := range regexes {
+		alternatives[i] = strings.TrimPrefix(regex, Flags())
+	}
+	return fmt.Sprintf("%s(?:%s)", Flags(), strings.Join(alternatives, "|"))
+}
+
 // visible for testing
 func Flags() string {
 	return "(?im)"
@@ -54,13 +106,13 @@ func Flags() string {
 
 // visible for testing
 func OpeningLine(styles []CommentStyle) string {
-	openingLine := fmt.Sprintf(`([\t\v\f\r ]*%s[\t\v\f\r ]*\n)?`, combineRegexes(styles, func(style CommentStyle) string { return style.GetOpeningString() }))
+	openingLine := fmt.Sprintf(`([\t\v\f\r ]*%s[\t\v\f\r ]*\n)?`, combineRegexes(styles, openingRegex))
 	return openingLine
 }
 
 // visible for testing
 func MatchingLine(line string, styles []CommentStyle) string {
-	openingStyleSymbolRegex := combineRegexes(styles, func(style CommentStyle) string { return style.GetString() })
+	openingStyleSymbolRegex := combineRegexes(styles, prefixRegex)
 	normalizedLine := normalizePunctuation(line)
 	middleLine := fmt.Sprintf(`[\t\v\f\r ]*%s?[\t\v\f\r ]*\Q%s\E[,.;:?!\t\v\f\r ]*\n?`, openingStyleSymbolRegex, normalizedLine)
 	builder := strings.Builder{}
@@ -69,6 +121,19 @@ func MatchingLine(line string, styles []CommentStyle) string {
 }
 
 func normalizePunctuation(line string) string {
+	// template actions are left untouched, injectDataRegex turns them into wildcards
+	builder := strings.Builder{}
+	offset := 0
+	for _, location := range actionRegex.FindAllStringIndex(line, -1) {
+		builder.WriteString(normalizeText(line[offset:location[0]]))
+		builder.WriteString(line[location[0]:location[1]])
+		offset = location[1]
+	}
+	builder.WriteString(normalizeText(line[offset:]))
+	return builder.String()
+}
+
+func normalizeText(line string) string {
 	ignore := `\E.?\Q`
 	normalizedLine := ""
 	// we could use a(n only) slightly better heuristic with a regex matching all dots
@@ -103,40 +168,58 @@ func normalizePunctuation(line string) string {
 
 // visible for testing
 func ClosingLine(styles []CommentStyle) string {
-	closingLine := fmt.Sprintf(`(?:[\t\v\f\r ]*%s[\t\v\f\r ]*)?`, combineRegexes(styles, func(style CommentStyle) string { return style.GetClosingString() }))
+	closingLine := fmt.Sprintf(`(?:[\t\v\f\r ]*%s[\t\v\f\r ]*)?`, combineRegexes(styles, closingRegex))
 	return closingLine
 }
 
 func commentedEmptyLine(styles []CommentStyle) string {
-	emptyLines := combineRegexes(styles, func(style CommentStyle) string { return style.GetString() })
-	return fmt.Sprintf(`(?:%s?\n)*`, emptyLines)
+	emptyLines := combineRegexes(styles, prefixRegex)
+	return fmt.Sprintf(`(?:%s?\r?\n)*`, emptyLines)
 }
 
-func combineRegexes(styles []CommentStyle, getLine func(CommentStyle) string) string {
+func combineRegexes(styles []CommentStyle, getRegex func(CommentStyle) string) string {
 	regexes := make([]string, 0)
 	for _, style := range styles {
-		commentSymbol := getLine(style)
-		if line := commentSymbol; line != "" {
-			regex := escape(line)
-			// spaces may be formatted away - make the space optional
-			regex = strings.Replace(regex, " ", " ?", -1)
+		if regex := getRegex(style); regex != "" {
 			regexes = append(regexes, regex)
 		}
 	}
 	return fmt.Sprintf("(?:%s)", strings.Join(regexes, "|"))
 }
 
+func openingRegex(style CommentStyle) string {
+	if variants, ok := style.(DelimiterVariants); ok {
+		return variants.GetOpeningRegex()
+	}
+	return commentSymbolRegex(style.GetOpeningString())
+}
+
+func prefixRegex(style CommentStyle) string {
+	return commentSymbolRegex(style.GetString())
+}
+
+func closingRegex(style CommentStyle) string {
+	if variants, ok := style.(DelimiterVariants); ok {
+		return variants.GetClosingRegex()
+	}
+	return commentSymbolRegex(style.GetClosingString())
+}
+
+func commentSymbolRegex(symbol string) string {
+	// spaces may be formatted away - make the space optional
+	return strings.Replace(escape(symbol), " ", " ?", -1)
+}
+
 func escape(str string) string {
 	return strings.Replace(regexp.QuoteMeta(str), "/", `\/`, -1)
 }
 
-func injectDataRegex(result string, data map[string]string) (string, error) {
-	template, err := tpl.New("header-regex").Parse(result)
+func injectDataRegex(result string, templateParameters map[string]string) (string, error) {
+	template, err := tpl.New("header-regex").Funcs(regexFunctions()).Parse(result)
 	if err != nil {
 		return "", err
 	}
 	builder := &strings.Builder{}
-	templateParameters := regexValues(data)
 	err = template.Execute(builder, templateParameters)
 	if err != nil {
 		return "", err
@@ -152,13 +235,19 @@ func regexValues(data map[string]string) map[string]string {
 	return result
 }
 
-func extractValues(commentStyles map[string]CommentStyle) []CommentStyle {
-	result := make([]CommentStyle, len(commentStyles))
-	i := 0
-	for _, v := range commentStyles {
-		result[i] = v
-		i++
+// matches the data values as they are, except for the reserved parameters and the multi-line values
+func literalRegexValues(data map[string]string) map[string]string {
+	result := regexValues(data)
+	for key, value := range data {
+		if !isReservedParameter(key) && !strings.Contains(value, "\n") {
+			result[key] = normalizeText(value)
+		}
 	}
+	return result
+}
+
+func sortedStyles(commentStyles []CommentStyle) []CommentStyle {
+	result := append([]CommentStyle{}, commentStyles...)
 	sort.SliceStable(result, CommentStyleSorter(result))
 	return result
 }
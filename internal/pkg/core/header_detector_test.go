@@ -179,6 +179,229 @@ world`
 		})
 	})
 
+	Context("with ML-style block comments", func() {
+
+		const file = `(*
+ * some multi-line header
+ * with some text
+ *)
+let () = print_endline "hello"`
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 50}))
+		})
+	})
+
+	Context("with Haskell-style block comments", func() {
+
+		const file = `{-
+ - some multi-line header
+ - with some text
+ -}
+module Main where`
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 50}))
+		})
+	})
+
+	Context("with percent-sign line comments", func() {
+
+		const file = `% some multi-line header
+% with some text
+\documentclass{article}`
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 42}))
+		})
+	})
+
+	Context("with Erlang double percent-sign line comments", func() {
+
+		const file = `%% some multi-line header
+%% with some text
+-module(hello).`
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 44}))
+		})
+	})
+
+	Context("with exclamation mark line comments", func() {
+
+		const file = `! some multi-line header
+! with some text
+program hello`
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 42}))
+		})
+	})
+
+	Context("with Lua long comments", func() {
+
+		const file = `--[[
+some multi-line header
+with some text
+]]
+print("hello")`
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 45}))
+		})
+	})
+
+	Context("with Lua long comments of a higher level", func() {
+
+		const file = `--[==[
+some multi-line header
+with some text
+]==]
+print("hello")`
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 49}))
+		})
+	})
+
+	Context("with Python docstrings", func() {
+
+		const file = `"""
+some multi-line header
+with some text
+"""
+import os`
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 45}))
+		})
+	})
+
+	Context("with Jinja comments", func() {
+
+		const file = `{#
+some multi-line header
+with some text
+#}
+<html>`
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 43}))
+		})
+	})
+
+	Context("with JSP comments", func() {
+
+		const file = `<%--
+some multi-line header
+with some text
+--%>
+<%@ page language="java" %>`
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 47}))
+		})
+	})
+
+	Context("with Handlebars comments", func() {
+
+		const file = `{{!--
+some multi-line header
+with some text
+--}}
+<div>{{title}}</div>`
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 48}))
+		})
+	})
+
+	Context("with template engine comments", func() {
+
+		It("should detect the headers commented in these styles", func() {
+			lines := []string{"some multi-line header", "with some text"}
+			regex, err := core.ComputeHeaderDetectionRegex(lines, map[string]string{})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, style := range []string{"Jinja", "JSP", "Handlebars"} {
+				commentedLines, err := core.ApplyComments(lines, core.ParseCommentStyle(style))
+				Expect(err).NotTo(HaveOccurred())
+				header := strings.Join(commentedLines, "\n")
+
+				Expect(regexp.MustCompile(regex).FindString(header+"\n<p>hello</p>")).To(Equal(header), style)
+			}
+		})
+	})
+
+	Context("with CRLF line endings", func() {
+
+		const file = "/*\r\n * some multi-line header\r\n *\r\n * with some text\r\n */\r\nhello\r\nworld"
+
+		It("should detect it", func() {
+			regex, err := core.ComputeHeaderDetectionRegex(
+				[]string{"some multi-line header", "", "with some text"},
+				map[string]string{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 58}))
+		})
+	})
+
 	Context("with a realistic header", func() {
 
 		const template = `Copyright {{.YearRange}} {{.Owner}}
@@ -248,6 +471,19 @@ func main() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(matchLeftMostPositions(regex, file)).To(Equal([]int{0, 610}))
 		})
+
+		It("computes a regex to match wrapped headers", func() {
+			templateLines := []string{"Copyright {{.YearRange}} {{.Owner}}", "Licensed under the Apache License, Version 2.0"}
+			templateParameters := map[string]string{"Owner": "", "YearRange": ""}
+
+			regex, err := core.ComputeWrappedHeaderDetectionRegex(templateLines, templateParameters)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(regexp.MustCompile(regex).MatchString("// Copyright 2020 Jane Doe,\n// John Smith\n// Licensed under the\n// Apache License, Version 2.0\n")).
+				To(BeTrue(), "matches the wrapped header")
+			Expect(regexp.MustCompile(regex).MatchString("// Copyright 2020 ACME\n// Licensed under the Apache License, Version 2.0\n")).
+				To(BeTrue(), "matches the unwrapped header")
+		})
 	})
 })
 
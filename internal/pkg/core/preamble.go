@@ -0,0 +1,149 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// UTF-8 byte order mark, always kept at the very beginning of the file
+const byteOrderMark = "\uFEFF"
+
+// interpreter directive of executable scripts
+const shebangPreamble = `#!.*`
+
+// Returns the regexes of the lines that must stay above the header, by comment style name
+// Styles without specific preamble only keep shebangs above the header
+func DefaultStylePreambles() map[string][]string {
+	// see https://peps.python.org/pep-0263/
+	pythonEncoding := `[ \t\f]*#.*?coding[:=][ \t]*[-_.a-zA-Z0-9]+.*`
+	// see https://docs.docker.com/reference/dockerfile/#parser-directives
+	dockerfileDirective := `#[ \t]*(?i:syntax|escape|check)[ \t]*=.*`
+	return map[string][]string{
+		"hash":      {shebangPreamble, pythonEncoding, dockerfileDirective},
+		"docstring": {shebangPreamble, pythonEncoding},
+		"xml":       {`<\?xml.*\?>`},
+	}
+}
+
+// Returns the regexes of the lines that must stay above the header, by file extension
+// These take precedence over the preambles of the comment style
+func DefaultExtensionPreambles() map[string][]string {
+	return map[string][]string{
+		"php": {shebangPreamble, `<\?php.*`},
+	}
+}
+
+// Returns the preamble regexes of the file, based on its extension and then on its comment style
+func (changeSet *ChangeSet) PreambleOf(path string) []*regexp.Regexp {
+	if regexes, found := changeSet.ExtensionPreambles[FileExtension(path)]; found {
+		return regexes
+	}
+	style := changeSet.CommentStyleOf(path)
+	if style == nil {
+		return nil
+	}
+	return changeSet.StylePreambles[strings.ToLower(style.GetName())]
+}
+
+// compiles the preamble regexes of every supported comment style, the configured ones replacing the default ones
+func compileStylePreambles(configuredPreambles map[string][]string, styles []CommentStyle) (map[string][]*regexp.Regexp, error) {
+	defaults := DefaultStylePreambles()
+	result := make(map[string][]*regexp.Regexp)
+	for _, name := range namesOf(styles) {
+		preambles, found := configuredPreambles[name]
+		if !found {
+			preambles, found = defaults[name]
+		}
+		if !found {
+			preambles = []string{shebangPreamble}
+		}
+		regexes, err := compilePreambles(preambles)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = regexes
+	}
+	return result, nil
+}
+
+// compiles the preamble regexes by file extension, the configured ones replacing the default ones
+func compileExtensionPreambles(configuredPreambles map[string][]string) (map[string][]*regexp.Regexp, error) {
+	preamblesByExtension := DefaultExtensionPreambles()
+	for extension, preambles := range configuredPreambles {
+		preamblesByExtension[strings.TrimPrefix(extension, ".")] = preambles
+	}
+	result := make(map[string][]*regexp.Regexp, len(preamblesByExtension))
+	for extension, preambles := range preamblesByExtension {
+		regexes, err := compilePreambles(preambles)
+		if err != nil {
+			return nil, err
+		}
+		result[extension] = regexes
+	}
+	return result, nil
+}
+
+// preamble regexes match whole lines
+func compilePreambles(preambles []string) ([]*regexp.Regexp, error) {
+	result := make([]*regexp.Regexp, len(preambles))
+	for i, preamble := range preambles {
+		regex, err := regexp.Compile(fmt.Sprintf(`^(?:%s)$`, preamble))
+		if err != nil {
+			return nil, fmt.Errorf("invalid preamble regex %q: %w", preamble, err)
+		}
+		result[i] = regex
+	}
+	return result, nil
+}
+
+// splits the contents into the preamble, made of the byte order mark and the leading lines matching any of the
+// regexes, and the rest of the contents
+func splitPreamble(contents string, regexes []*regexp.Regexp) (string, string) {
+	offset := 0
+	if strings.HasPrefix(contents, byteOrderMark) {
+		offset = len(byteOrderMark)
+	}
+	for offset < len(contents) {
+		lineEnd := strings.Index(contents[offset:], "\n") + 1
+		if lineEnd == 0 {
+			lineEnd = len(contents) - offset
+		}
+		line := strings.TrimRight(contents[offset:offset+lineEnd], "\r\n")
+		if !matchesAny(line, regexes) {
+			break
+		}
+		offset += lineEnd
+	}
+	preamble := contents[:offset]
+	if preamble != "" && preamble != byteOrderMark && !strings.HasSuffix(preamble, "\n") {
+		// the header goes on the next line
+		preamble += "\n"
+	}
+	return preamble, contents[offset:]
+}
+
+func matchesAny(line string, regexes []*regexp.Regexp) bool {
+	for _, regex := range regexes {
+		if regex.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
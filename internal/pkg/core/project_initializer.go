@@ -0,0 +1,149 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/fbiville/headache/internal/pkg/fs"
+	"github.com/fbiville/headache/internal/pkg/license"
+)
+
+// directories commonly holding third-party or generated files
+var excludedDirectoryCandidates = []string{"build", "dist", "node_modules", "target", "third_party", "vendor"}
+
+// Returns the comment style of well-known file extensions
+func DefaultExtensionStyles() map[string]string {
+	result := make(map[string]string)
+	register := func(style CommentStyle, extensions ...string) {
+		for _, extension := range extensions {
+			result[extension] = style.GetName()
+		}
+	}
+	catalog := SupportedStyleCatalog()
+	register(catalog["SlashStar"], "c", "cc", "cpp", "cs", "css", "dart", "go", "gradle", "groovy", "h", "hpp", "java",
+		"js", "jsx", "kt", "kts", "less", "proto", "rs", "scala", "scss", "swift", "ts", "tsx")
+	register(catalog["Hash"], "bash", "cmake", "conf", "pl", "properties", "ps1", "py", "r", "rb", "sh", "tf", "toml",
+		"yaml", "yml", "zsh")
+	register(catalog["DashDash"], "ada", "sql")
+	register(catalog["BraceDash"], "hs")
+	register(catalog["Lua"], "lua")
+	register(catalog["SemiColon"], "asm", "clj", "cljs", "el", "ini", "lisp", "scm")
+	register(catalog["REM"], "bat", "cmd")
+	register(catalog["XML"], "htm", "html", "svg", "vue", "xhtml", "xml", "xsd", "xsl")
+	register(catalog["SingleQuote"], "bas", "vb", "vbs")
+	register(catalog["ParenStar"], "dpr", "ml", "mli", "pas")
+	register(catalog["Percent"], "bib", "cls", "ps", "sty", "tex")
+	register(catalog["PercentPercent"], "erl", "hrl")
+	register(catalog["Exclamation"], "f03", "f08", "f90", "f95")
+	register(catalog["Jinja"], "j2", "jinja", "jinja2", "twig")
+	register(catalog["Handlebars"], "handlebars", "hbs")
+	register(catalog["JSP"], "ascx", "aspx", "jsp", "jspf")
+	return result
+}
+
+type ProjectInitializer struct {
+	FileSystem  *fs.FileSystem
+	PathMatcher fs.PathMatcher
+}
+
+// Scans the project files and proposes a configuration covering all the detected languages
+// The most common comment style becomes the default one, the others are configured by file extension
+func (initializer *ProjectInitializer) Propose(headerFile string, owner string) (*Configuration, error) {
+	excludes := make([]string, 0)
+	for _, directory := range excludedDirectoryCandidates {
+		if initializer.isDirectory(directory) {
+			excludes = append(excludes, directory+"/**/*")
+		}
+	}
+	files, err := initializer.PathMatcher.ScanAllFiles([]string{"**/*"}, append([]string{".git/**/*"}, excludes...), initializer.FileSystem)
+	if err != nil {
+		return nil, err
+	}
+
+	knownStyles := DefaultExtensionStyles()
+	extensionStyles := make(map[string]string)
+	styleCounts := make(map[string]int)
+	for _, file := range files {
+		extension := FileExtension(file.Path)
+		if style, found := knownStyles[extension]; found {
+			extensionStyles[extension] = style
+			styleCounts[style]++
+		}
+	}
+	if len(extensionStyles) == 0 {
+		return nil, fmt.Errorf("no source file with a supported extension found")
+	}
+
+	defaultStyle := mostCommonStyle(styleCounts)
+	includes := make([]string, 0, len(extensionStyles))
+	for extension, style := range extensionStyles {
+		includes = append(includes, "**/*."+extension)
+		if style == defaultStyle {
+			delete(extensionStyles, extension)
+		}
+	}
+	sort.Strings(includes)
+	if len(extensionStyles) == 0 {
+		extensionStyles = nil
+	}
+	return &Configuration{
+		HeaderFile:      headerFile,
+		CommentStyle:    defaultStyle,
+		ExtensionStyles: extensionStyles,
+		Includes:        includes,
+		Excludes:        excludes,
+		TemplateData:    map[string]string{"Owner": owner},
+	}, nil
+}
+
+// Writes the header template of the license and the configuration referencing it
+func (initializer *ProjectInitializer) Write(configurationPath string, configuration *Configuration, licenseId string) error {
+	header, err := license.Header(licenseId)
+	if err != nil {
+		return err
+	}
+	configurationPayload, err := json.MarshalIndent(configuration, "", "  ")
+	if err != nil {
+		return err
+	}
+	writer := initializer.FileSystem.FileWriter
+	if err := writer.Write(configuration.HeaderFile, header, 0644); err != nil {
+		return fmt.Errorf("cannot write header template %v: %w", configuration.HeaderFile, err)
+	}
+	if err := writer.Write(configurationPath, string(configurationPayload)+"\n", 0644); err != nil {
+		return fmt.Errorf("cannot write configuration %v: %w", configurationPath, err)
+	}
+	return nil
+}
+
+func (initializer *ProjectInitializer) isDirectory(path string) bool {
+	info, err := initializer.FileSystem.FileReader.Stat(path)
+	return err == nil && info.Mode().IsDir()
+}
+
+func mostCommonStyle(styleCounts map[string]int) string {
+	result := ""
+	for style, count := range styleCounts {
+		if result == "" || count > styleCounts[result] || (count == styleCounts[result] && style < result) {
+			result = style
+		}
+	}
+	return result
+}
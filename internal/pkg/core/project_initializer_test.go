@@ -0,0 +1,136 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fbiville/headache/internal/pkg/core"
+	. "github.com/fbiville/headache/internal/pkg/fs"
+	"github.com/fbiville/headache/internal/pkg/fs_mocks"
+	"github.com/fbiville/headache/internal/pkg/vcs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("Project initializer", func() {
+
+	var (
+		t           GinkgoTInterface
+		fileReader  *fs_mocks.FileReader
+		fileWriter  *fs_mocks.FileWriter
+		fileSystem  *FileSystem
+		pathMatcher *fs_mocks.PathMatcher
+		initializer *core.ProjectInitializer
+	)
+
+	BeforeEach(func() {
+		t = GinkgoT()
+		fileReader = new(fs_mocks.FileReader)
+		fileWriter = new(fs_mocks.FileWriter)
+		fileSystem = &FileSystem{FileReader: fileReader, FileWriter: fileWriter}
+		pathMatcher = new(fs_mocks.PathMatcher)
+		initializer = &core.ProjectInitializer{FileSystem: fileSystem, PathMatcher: pathMatcher}
+	})
+
+	AfterEach(func() {
+		fileReader.AssertExpectations(t)
+		fileWriter.AssertExpectations(t)
+		pathMatcher.AssertExpectations(t)
+	})
+
+	It("proposes includes, excludes and comment styles of the detected languages", func() {
+		for _, directory := range []string{"build", "dist", "node_modules", "target", "third_party"} {
+			fileReader.On("Stat", directory).Return(nil, os.ErrNotExist)
+		}
+		fileReader.On("Stat", "vendor").Return(&FakeFileInfo{FileMode: os.ModeDir}, nil)
+		pathMatcher.On("ScanAllFiles", []string{"**/*"}, []string{".git/**/*", "vendor/**/*"}, fileSystem).
+			Return([]vcs.FileChange{
+				{Path: "main.go"},
+				{Path: "pkg/lib.go"},
+				{Path: "web/app.js"},
+				{Path: "scripts/release.sh"},
+				{Path: "tools/Main.hs"},
+				{Path: "plugins/init.lua"},
+				{Path: "README.md"},
+			}, nil)
+
+		configuration, err := initializer.Propose("license-header.txt", "ACME")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configuration).To(Equal(&core.Configuration{
+			HeaderFile:      "license-header.txt",
+			CommentStyle:    "SlashStar",
+			ExtensionStyles: map[string]string{"hs": "BraceDash", "lua": "Lua", "sh": "Hash"},
+			Includes:        []string{"**/*.go", "**/*.hs", "**/*.js", "**/*.lua", "**/*.sh"},
+			Excludes:        []string{"vendor/**/*"},
+			TemplateData:    map[string]string{"Owner": "ACME"},
+		}))
+	})
+
+	It("fails when no supported source file is found", func() {
+		fileReader.On("Stat", mock.Anything).Return(nil, os.ErrNotExist)
+		pathMatcher.On("ScanAllFiles", []string{"**/*"}, []string{".git/**/*"}, fileSystem).
+			Return([]vcs.FileChange{{Path: "README.md"}}, nil)
+
+		_, err := initializer.Propose("license-header.txt", "ACME")
+
+		Expect(err).To(MatchError("no source file with a supported extension found"))
+	})
+
+	It("writes the license header template and a schema-valid configuration", func() {
+		configuration := &core.Configuration{
+			HeaderFile:      "license-header.txt",
+			CommentStyle:    "SlashStar",
+			ExtensionStyles: map[string]string{"sh": "Hash"},
+			Includes:        []string{"**/*.go", "**/*.sh"},
+			TemplateData:    map[string]string{"Owner": "ACME"},
+		}
+		var writtenConfiguration string
+		fileWriter.On("Write", "license-header.txt", mock.MatchedBy(func(header string) bool {
+			return header == "Copyright {{.YearRange}} {{.Owner}}\n\n"+
+				"This Source Code Form is subject to the terms of the Mozilla Public\n"+
+				"License, v. 2.0. If a copy of the MPL was not distributed with this\n"+
+				"file, You can obtain one at https://mozilla.org/MPL/2.0/."
+		}), os.FileMode(0644)).Return(nil)
+		fileWriter.On("Write", "headache.json", mock.Anything, os.FileMode(0644)).
+			Run(func(args mock.Arguments) { writtenConfiguration = args.String(1) }).
+			Return(nil)
+
+		err := initializer.Write("headache.json", configuration, "MPL-2.0")
+
+		Expect(err).NotTo(HaveOccurred())
+		reader := new(fs_mocks.FileReader)
+		reader.On("Read", "headache.json").Return([]byte(writtenConfiguration), nil)
+		loader := core.ConfigurationFileLoader{
+			Reader:         reader,
+			SchemaLocation: "file://../../../docs/schema.json",
+			SchemaLoader:   &LocalSchemaLoader{},
+		}
+		loadedConfiguration, err := loader.ValidateAndLoad("headache.json")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loadedConfiguration.ExtensionStyles).To(Equal(map[string]string{"sh": "hash"}))
+	})
+
+	It("does not write anything with an unsupported license", func() {
+		err := initializer.Write("headache.json", &core.Configuration{}, "WTFPL")
+
+		Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("unsupported license %q", "WTFPL"))))
+	})
+})
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fbiville/headache/internal/pkg/fs"
+)
+
+type ReuseFormat string
+
+const (
+	// REUSE.toml file, see https://reuse.software/spec-3.3/#reusetoml
+	ReuseToml ReuseFormat = "toml"
+	// Debian copyright file, deprecated by REUSE.toml but still supported by the REUSE tooling
+	ReuseDep5 ReuseFormat = "dep5"
+)
+
+// markers delimiting the annotation block maintained by headache, the rest of the file is left untouched
+const (
+	reuseBlockStart = "# headache:begin"
+	reuseBlockEnd   = "# headache:end"
+)
+
+// data parameter holding the copyright holder of the annotated files, unless configured otherwise
+const defaultAnnotationHolderKey = "Owner"
+
+// Returns the conventional location of the REUSE file of this format
+func (format ReuseFormat) DefaultPath() string {
+	if format == ReuseDep5 {
+		return ".reuse/dep5"
+	}
+	return "REUSE.toml"
+}
+
+type ReuseAnnotator struct {
+	Environment *Environment
+	PathMatcher fs.PathMatcher
+}
+
+// Computes the annotation block declaring the license of the configured annotation globs
+// The copyright years span the history of all the files matched by these globs
+func (annotator *ReuseAnnotator) Annotate(configuration *Configuration, format ReuseFormat) (string, error) {
+	files, err := annotator.PathMatcher.ScanAllFiles(configuration.Annotations, configuration.Excludes, annotator.Environment.FileSystem)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no file matches the annotation globs %s", strings.Join(configuration.Annotations, ","))
+	}
+	files, err = annotator.Environment.VersioningClient.AddMetadata(files, annotator.Environment.Clock)
+	if err != nil {
+		return "", err
+	}
+	startYear, endYear := files[0].CreationYear, files[0].LastEditionYear
+	for _, file := range files[1:] {
+		if file.CreationYear < startYear {
+			startYear = file.CreationYear
+		}
+		if file.LastEditionYear > endYear {
+			endYear = file.LastEditionYear
+		}
+	}
+	holderKey := configuration.AnnotationHolderKey
+	if holderKey == "" {
+		holderKey = defaultAnnotationHolderKey
+	}
+	copyright := strings.TrimSpace(fmt.Sprintf("%s %s", yearRange(startYear, endYear), configuration.TemplateData[holderKey]))
+
+	if format == ReuseDep5 {
+		return dep5Annotation(configuration.Annotations, copyright, configuration.License), nil
+	}
+	return tomlAnnotation(configuration.Annotations, copyright, configuration.License), nil
+}
+
+// Writes the annotation block to the REUSE file, replacing the previously written block if any
+// Returns whether the file contents changed
+func (annotator *ReuseAnnotator) Write(path string, annotation string, format ReuseFormat) (bool, error) {
+	fileSystem := annotator.Environment.FileSystem
+	existingContents := ""
+	exists := fileSystem.IsFile(path)
+	if exists {
+		bytes, err := fileSystem.FileReader.Read(path)
+		if err != nil {
+			return false, err
+		}
+		existingContents = string(bytes)
+	}
+
+	var newContents string
+	start := strings.Index(existingContents, reuseBlockStart)
+	end := strings.Index(existingContents, reuseBlockEnd)
+	switch {
+	case start != -1 && end > start:
+		newContents = existingContents[:start] + annotation + existingContents[end+len(reuseBlockEnd):]
+	case strings.TrimSpace(existingContents) == "":
+		newContents = reusePreamble(format) + annotation + "\n"
+	default:
+		newContents = strings.TrimRight(existingContents, "\n") + "\n\n" + annotation + "\n"
+	}
+	if newContents == existingContents {
+		return false, nil
+	}
+	if !exists {
+		if err := fileSystem.FileWriter.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return false, err
+		}
+	}
+	return true, fileSystem.FileWriter.Write(path, newContents, 0644)
+}
+
+func reusePreamble(format ReuseFormat) string {
+	if format == ReuseDep5 {
+		return "Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/\n\n"
+	}
+	return "version = 1\n\n"
+}
+
+func tomlAnnotation(globs []string, copyright string, licenseId string) string {
+	paths := make([]string, len(globs))
+	for i, glob := range globs {
+		paths[i] = fmt.Sprintf("%q", glob)
+	}
+	lines := []string{
+		reuseBlockStart,
+		"[[annotations]]",
+		fmt.Sprintf("path = [%s]", strings.Join(paths, ", ")),
+		`precedence = "aggregate"`,
+		fmt.Sprintf("SPDX-FileCopyrightText = %q", copyright),
+		fmt.Sprintf("SPDX-License-Identifier = %q", licenseId),
+		reuseBlockEnd,
+	}
+	return strings.Join(lines, "\n")
+}
+
+func dep5Annotation(globs []string, copyright string, licenseId string) string {
+	patterns := make([]string, len(globs))
+	for i, glob := range globs {
+		// wildcards of Debian copyright files match directory separators as well
+		patterns[i] = strings.Replace(strings.Replace(glob, "**/", "", -1), "**", "*", -1)
+	}
+	lines := []string{
+		reuseBlockStart,
+		fmt.Sprintf("Files: %s", strings.Join(patterns, " ")),
+		fmt.Sprintf("Copyright: %s", copyright),
+		fmt.Sprintf("License: %s", licenseId),
+		reuseBlockEnd,
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core_test
+
+import (
+	"os"
+
+	"github.com/fbiville/headache/internal/pkg/core"
+	. "github.com/fbiville/headache/internal/pkg/fs"
+	"github.com/fbiville/headache/internal/pkg/fs_mocks"
+	"github.com/fbiville/headache/internal/pkg/helper_mocks"
+	"github.com/fbiville/headache/internal/pkg/vcs"
+	"github.com/fbiville/headache/internal/pkg/vcs_mocks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("REUSE annotator", func() {
+
+	var (
+		t                GinkgoTInterface
+		fileReader       *fs_mocks.FileReader
+		fileWriter       *fs_mocks.FileWriter
+		fileSystem       *FileSystem
+		versioningClient *vcs_mocks.VersioningClient
+		clock            *helper_mocks.Clock
+		pathMatcher      *fs_mocks.PathMatcher
+		annotator        *core.ReuseAnnotator
+		configuration    *core.Configuration
+		tomlAnnotation   string
+	)
+
+	BeforeEach(func() {
+		t = GinkgoT()
+		fileReader = new(fs_mocks.FileReader)
+		fileWriter = new(fs_mocks.FileWriter)
+		fileSystem = &FileSystem{FileReader: fileReader, FileWriter: fileWriter}
+		versioningClient = new(vcs_mocks.VersioningClient)
+		clock = new(helper_mocks.Clock)
+		pathMatcher = new(fs_mocks.PathMatcher)
+		annotator = &core.ReuseAnnotator{
+			Environment: &core.Environment{FileSystem: fileSystem, VersioningClient: versioningClient, Clock: clock},
+			PathMatcher: pathMatcher,
+		}
+		configuration = &core.Configuration{
+			License:      "MIT",
+			Annotations:  []string{"testdata/**/*", "assets/**"},
+			Excludes:     []string{"vendor/**/*"},
+			TemplateData: map[string]string{"Owner": "ACME"},
+		}
+		tomlAnnotation = `# headache:begin
+[[annotations]]
+path = ["testdata/**/*", "assets/**"]
+precedence = "aggregate"
+SPDX-FileCopyrightText = "2016-2022 ACME"
+SPDX-License-Identifier = "MIT"
+# headache:end`
+	})
+
+	AfterEach(func() {
+		fileReader.AssertExpectations(t)
+		fileWriter.AssertExpectations(t)
+		versioningClient.AssertExpectations(t)
+		pathMatcher.AssertExpectations(t)
+	})
+
+	Describe("when computing the annotation", func() {
+
+		BeforeEach(func() {
+			files := []vcs.FileChange{{Path: "testdata/a.json"}, {Path: "assets/logo.png"}}
+			pathMatcher.On("ScanAllFiles", configuration.Annotations, configuration.Excludes, fileSystem).
+				Return(files, nil)
+			versioningClient.On("AddMetadata", files, clock).Return([]vcs.FileChange{
+				{Path: "testdata/a.json", CreationYear: 2018, LastEditionYear: 2022},
+				{Path: "assets/logo.png", CreationYear: 2016, LastEditionYear: 2019},
+			}, nil)
+		})
+
+		It("spans the history of all the annotated files in REUSE.toml", func() {
+			annotation, err := annotator.Annotate(configuration, core.ReuseToml)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(annotation).To(Equal(tomlAnnotation))
+		})
+
+		It("spans the history of all the annotated files in dep5", func() {
+			annotation, err := annotator.Annotate(configuration, core.ReuseDep5)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(annotation).To(Equal(`# headache:begin
+Files: testdata/* assets/*
+Copyright: 2016-2022 ACME
+License: MIT
+# headache:end`))
+		})
+
+		It("reads the copyright holder from the configured data parameter", func() {
+			configuration.AnnotationHolderKey = "Company"
+			configuration.TemplateData["Company"] = "Wile E. Coyote Inc."
+
+			annotation, err := annotator.Annotate(configuration, core.ReuseDep5)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(annotation).To(ContainSubstring("Copyright: 2016-2022 Wile E. Coyote Inc.\n"))
+		})
+	})
+
+	Describe("when writing the annotation", func() {
+
+		It("creates the REUSE file", func() {
+			fileReader.On("Stat", "REUSE.toml").Return(nil, os.ErrNotExist)
+			fileWriter.On("MkdirAll", ".", os.FileMode(0755)).Return(nil)
+			fileWriter.On("Write", "REUSE.toml", "version = 1\n\n"+tomlAnnotation+"\n", os.FileMode(0644)).Return(nil)
+
+			changed, err := annotator.Write("REUSE.toml", tomlAnnotation, core.ReuseToml)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeTrue())
+		})
+
+		It("creates the directory of the REUSE file", func() {
+			dep5Annotation := "# headache:begin\nFiles: testdata/*\n# headache:end"
+			fileReader.On("Stat", ".reuse/dep5").Return(nil, os.ErrNotExist)
+			fileWriter.On("MkdirAll", ".reuse", os.FileMode(0755)).Return(nil)
+			fileWriter.On("Write", ".reuse/dep5", "Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/\n\n"+dep5Annotation+"\n", os.FileMode(0644)).
+				Return(nil)
+
+			changed, err := annotator.Write(".reuse/dep5", dep5Annotation, core.ReuseDep5)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeTrue())
+		})
+
+		It("appends the annotation to the existing REUSE file", func() {
+			fileReader.On("Stat", "REUSE.toml").Return(&FakeFileInfo{FileMode: 0644}, nil)
+			fileReader.On("Read", "REUSE.toml").Return([]byte("version = 1\n\n[[annotations]]\npath = \"docs/**\"\n"), nil)
+			fileWriter.On("Write", "REUSE.toml", "version = 1\n\n[[annotations]]\npath = \"docs/**\"\n\n"+tomlAnnotation+"\n", os.FileMode(0644)).
+				Return(nil)
+
+			changed, err := annotator.Write("REUSE.toml", tomlAnnotation, core.ReuseToml)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeTrue())
+		})
+
+		It("replaces the previously written annotation", func() {
+			previousAnnotation := "# headache:begin\n[[annotations]]\npath = [\"testdata/**/*\"]\n# headache:end"
+			fileReader.On("Stat", "REUSE.toml").Return(&FakeFileInfo{FileMode: 0644}, nil)
+			fileReader.On("Read", "REUSE.toml").Return([]byte("version = 1\n\n"+previousAnnotation+"\n\n# more\n"), nil)
+			fileWriter.On("Write", "REUSE.toml", "version = 1\n\n"+tomlAnnotation+"\n\n# more\n", os.FileMode(0644)).Return(nil)
+
+			changed, err := annotator.Write("REUSE.toml", tomlAnnotation, core.ReuseToml)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeTrue())
+		})
+
+		It("leaves the up-to-date annotation untouched", func() {
+			fileReader.On("Stat", "REUSE.toml").Return(&FakeFileInfo{FileMode: 0644}, nil)
+			fileReader.On("Read", "REUSE.toml").Return([]byte("version = 1\n\n"+tomlAnnotation+"\n"), nil)
+
+			changed, err := annotator.Write("REUSE.toml", tomlAnnotation, core.ReuseToml)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeFalse())
+		})
+	})
+})
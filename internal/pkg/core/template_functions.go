@@ -0,0 +1,124 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	tpl "text/template"
+	"unicode/utf8"
+
+	"github.com/fbiville/headache/internal/pkg/helper"
+	"github.com/mattn/go-zglob"
+)
+
+// functions available in header templates
+func templateFunctions() tpl.FuncMap {
+	return tpl.FuncMap{
+		"upper":   strings.ToUpper,
+		"lower":   strings.ToLower,
+		"trim":    strings.TrimSpace,
+		"default": defaultValue,
+		"env":     os.Getenv,
+		"replace": replace,
+		"wrap":    wrap,
+	}
+}
+
+// functions depending on the file the template is executed for
+func fileFunctions(path string) tpl.FuncMap {
+	return tpl.FuncMap{
+		// e.g. {{if matches "third_party/**"}}
+		"matches": func(pattern string) bool {
+			matched, err := zglob.Match(pattern, filepath.ToSlash(path))
+			return err == nil && matched
+		},
+	}
+}
+
+// functions depending on the time of the execution, read from the given clock
+func clockFunctions(clock helper.Clock) tpl.FuncMap {
+	return tpl.FuncMap{
+		// formats the current time with the given Go layout, e.g. {{now "2006"}}
+		"now": func(layout string) string {
+			return clock.Now().Format(layout)
+		},
+	}
+}
+
+// names of the functions executed by the second pass of the template only, along with the reserved parameters
+func deferredFunctionNames() []string {
+	result := make([]string, 0)
+	for name := range fileFunctions("") {
+		result = append(result, name)
+	}
+	for name := range clockFunctions(nil) {
+		result = append(result, name)
+	}
+	return result
+}
+
+// functions of the detection regex: function results are matched by wildcards, as they may vary from file to file
+// or from one execution to the next
+func regexFunctions() tpl.FuncMap {
+	result := tpl.FuncMap{}
+	for name := range templateFunctions() {
+		result[name] = func(...interface{}) string { return `\E.*\Q` }
+	}
+	for _, name := range deferredFunctionNames() {
+		result[name] = func(...interface{}) string { return `\E.*\Q` }
+	}
+	// wrapped text spans several lines
+	result["wrap"] = func(...interface{}) string { return `\E[\s\S]*?\Q` }
+	return result
+}
+
+// returns the value, or the default value if the former is empty, e.g. {{.Owner | default "ACME"}}
+func defaultValue(defaultValue string, value string) string {
+	if strings.TrimSpace(value) == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// replaces all occurrences of old with new in the text, e.g. {{.Owner | replace "Inc" "Incorporated"}}
+func replace(old string, new string, text string) string {
+	return strings.Replace(text, old, new, -1)
+}
+
+// breaks the text into lines of at most width characters, words longer than the width are left unbroken,
+// e.g. {{wrap 80 .Notice}}
+func wrap(width int, text string) string {
+	lines := make([]string, 0)
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -17,49 +17,227 @@
 package core
 
 import (
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	tpl "text/template"
 )
 
+var (
+	actionRegex            = regexp.MustCompile(`\{\{.*?\}\}`)
+	plainReferenceRegex    = regexp.MustCompile(`^\{\{-?\s*\.\w+\s*-?\}\}$`)
+	reservedReferenceRegex = regexp.MustCompile(fmt.Sprintf(`\.(?:%s)\b|\b(?:%s)\b`,
+		strings.Join(reservedParameterNames(), "|"), strings.Join(deferredFunctionNames(), "|")))
+	controlActionRegex = regexp.MustCompile(`^\{\{-?\s*(if|else|end|range|with)\b`)
+	controlLineRegex   = regexp.MustCompile(`^\s*\{\{-?\s*(?:if|else|end|range|with)\b.*\}\}\s*$`)
+)
+
 type ParsedTemplate struct { // visible for testing
 	ActualContent  string
 	DetectionRegex *regexp.Regexp
+	// detection regex of the current header, identical to DetectionRegex unless the header template changed
+	CurrentDetectionRegex *regexp.Regexp
+	// detection regex matching the data values of the templates instead of any value, such as the configured owner
+	DataDetectionRegex *regexp.Regexp
 }
 
 func ParseTemplate(versionedHeader *VersionedHeaderTemplate, style CommentStyle) (*ParsedTemplate, error) {
-	currentData := injectReservedYearParameter(versionedHeader.Current.Data)
-	commentedLines, err := ApplyComments(versionedHeader.Current.Lines, style)
+	currentData := injectReservedParameters(versionedHeader.Current.Data)
+	template, err := newTemplate("header", strings.Join(versionedHeader.Current.Lines, "\n"))
 	if err != nil {
 		return nil, err
 	}
-	template, err := tpl.New("header").Parse(strings.Join(commentedLines, "\n"))
+	builder := &strings.Builder{}
+	err = template.Execute(builder, currentData)
 	if err != nil {
 		return nil, err
 	}
-	builder := &strings.Builder{}
-	err = template.Execute(builder, currentData)
+	// comments are applied after the first pass, so that multi-line values are commented as well
+	// they are escaped since the commented template is parsed again for the second pass
+	commentedLines, err := ApplyComments(strings.Split(builder.String(), "\n"), escapeActionDelimiters(style))
 	if err != nil {
 		return nil, err
 	}
 
-	previousData := injectReservedYearParameter(versionedHeader.Previous.Data)
-	regex, err := ComputeHeaderDetectionRegex(versionedHeader.Previous.Lines, previousData)
+	styles := detectionStyles(versionedHeader)
+	previousData := injectReservedParameters(versionedHeader.Previous.Data)
+	regex, err := detectionRegex(versionedHeader.Previous, regexValues(previousData), styles)
+	if err != nil {
+		return nil, err
+	}
+	dataRegex, err := detectionRegex(versionedHeader.Previous, literalRegexValues(previousData), styles)
+	if err != nil {
+		return nil, err
+	}
+	for _, legacyTemplate := range versionedHeader.Legacy {
+		legacyData := injectReservedParameters(legacyTemplate.Data)
+		legacyRegex, err := detectionRegex(legacyTemplate, regexValues(legacyData), styles)
+		if err != nil {
+			return nil, err
+		}
+		legacyDataRegex, err := detectionRegex(legacyTemplate, literalRegexValues(legacyData), styles)
+		if err != nil {
+			return nil, err
+		}
+		regex = alternateRegexes(regex, legacyRegex)
+		dataRegex = alternateRegexes(dataRegex, legacyDataRegex)
+	}
+	currentRegex, err := detectionRegex(versionedHeader.Current, regexValues(currentData), styles)
+	if err != nil {
+		return nil, err
+	}
+	currentDataRegex, err := detectionRegex(versionedHeader.Current, literalRegexValues(currentData), styles)
 	if err != nil {
 		return nil, err
 	}
 	return &ParsedTemplate{
-		ActualContent:  builder.String(),
-		DetectionRegex: regexp.MustCompile(regex),
+		ActualContent:         strings.Join(commentedLines, "\n"),
+		DetectionRegex:        regexp.MustCompile(regex),
+		CurrentDetectionRegex: regexp.MustCompile(currentRegex),
+		DataDetectionRegex:    regexp.MustCompile(alternateRegexes(dataRegex, currentDataRegex)),
 	}, nil
 }
 
-// injects reserved parameter into template data map by setting values as template placeholders
+// headers of templates with a maximum line length may have been wrapped
+func detectionRegex(header *HeaderTemplate, values map[string]string, styles []CommentStyle) (string, error) {
+	if header.wrapsLines() {
+		return wrappedHeaderDetectionRegex(header.Lines, values, styles)
+	}
+	return headerDetectionRegex(header.Lines, values, styles)
+}
+
+// headers may be commented in the built-in styles, or in the custom styles of the current or former configurations,
+// so that the headers of a custom style removed since then are still detected
+func detectionStyles(versionedHeader *VersionedHeaderTemplate) []CommentStyle {
+	result := BuiltInStyles()
+	names := namesOf(result)
+	templates := append([]*HeaderTemplate{versionedHeader.Current, versionedHeader.Previous}, versionedHeader.Legacy...)
+	for _, template := range templates {
+		for _, style := range CustomCommentStyles(template.CustomStyles) {
+			if name := strings.ToLower(style.GetName()); !containsName(names, name) {
+				result = append(result, style)
+				names = append(names, name)
+			}
+		}
+	}
+	return result
+}
+
+// executes the first pass of the template, without applying any comment style
+func parseUncommentedTemplate(header *HeaderTemplate) (string, error) {
+	data := make(map[string]string, len(header.Data))
+	for key, value := range header.Data {
+		data[key] = value
+	}
+	template, err := newTemplate("header", strings.Join(header.Lines, "\n"))
+	if err != nil {
+		return "", err
+	}
+	builder := &strings.Builder{}
+	if err := template.Execute(builder, injectReservedParameters(data)); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// parses the template for its first pass, along with the template functions
+// actions and conditional sections depending on reserved parameters are deferred to the second pass
+func newTemplate(name string, text string) (*tpl.Template, error) {
+	return tpl.New(name).Funcs(templateFunctions()).Parse(deferReservedActions(trimControlLines(text)))
+}
+
+// turns actions such as {{upper .FileName}} into {{"{{upper .FileName}}"}}, so that the first pass outputs them as is
+// plain references such as {{.FileName}} are kept, the reserved parameters being injected as placeholders
+// conditional sections are deferred as a whole when one of their conditions depends on reserved parameters
+func deferReservedActions(text string) string {
+	locations := actionRegex.FindAllStringIndex(text, -1)
+	deferred := make([]bool, len(locations))
+	// index of the opening action of the enclosing blocks
+	openBlocks := make([]int, 0)
+	// index of the opening action of the block of each control action
+	blockOf := make(map[int]int)
+	for i, location := range locations {
+		action := text[location[0]:location[1]]
+		keyword := controlKeyword(action)
+		switch {
+		case keyword == "if" || keyword == "range" || keyword == "with":
+			openBlocks = append(openBlocks, i)
+			blockOf[i] = i
+		case (keyword == "else" || keyword == "end") && len(openBlocks) > 0:
+			blockOf[i] = openBlocks[len(openBlocks)-1]
+			if keyword == "end" {
+				openBlocks = openBlocks[:len(openBlocks)-1]
+			}
+		}
+		if reservedReferenceRegex.MatchString(action) && !plainReferenceRegex.MatchString(action) {
+			deferred[i] = true
+			if block, found := blockOf[i]; found {
+				deferred[block] = true
+			}
+		}
+	}
+	builder := strings.Builder{}
+	offset := 0
+	for i, location := range locations {
+		action := text[location[0]:location[1]]
+		builder.WriteString(text[offset:location[0]])
+		if block, found := blockOf[i]; deferred[i] || (found && deferred[block]) {
+			action = fmt.Sprintf("{{%s}}", strconv.Quote(action))
+		}
+		builder.WriteString(action)
+		offset = location[1]
+	}
+	builder.WriteString(text[offset:])
+	return builder.String()
+}
+
+// returns the keyword of control actions such as {{if .Extension}} or {{end}}, an empty string otherwise
+func controlKeyword(action string) string {
+	matches := controlActionRegex.FindStringSubmatch(action)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// lines only made of a control action trim the preceding line break, so that conditional sections do not leave
+// empty lines behind
+func trimControlLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if isControlLine(line) {
+			action := strings.TrimLeft(strings.TrimPrefix(strings.TrimSpace(line), "{{"), "- ")
+			lines[i] = "{{- " + action
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isControlLine(line string) bool {
+	return controlLineRegex.MatchString(line) && len(actionRegex.FindAllString(line, -1)) == 1
+}
+
+// injects reserved year and file parameters into template data map by setting values as template placeholders
 // the template will be parsed a second time, file by file, with the actual values
-func injectReservedYearParameter(currentData map[string]string) map[string]string {
+func injectReservedParameters(currentData map[string]string) map[string]string {
 	currentData["Year"] = "{{.YearRange}}" // deprecated but kept for backwards compatibility
-	currentData["YearRange"] = "{{.YearRange}}"
-	currentData["StartYear"] = "{{.StartYear}}"
-	currentData["EndYear"] = "{{.EndYear}}"
+	for _, name := range reservedParameterNames()[1:] {
+		currentData[name] = fmt.Sprintf("{{.%s}}", name)
+	}
 	return currentData
 }
+
+func isReservedParameter(name string) bool {
+	for _, reservedName := range reservedParameterNames() {
+		if name == reservedName {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the names of the parameters substituted file by file, starting with the deprecated Year parameter
+func reservedParameterNames() []string {
+	return append([]string{"Year", "YearRange", "StartYear", "EndYear", "YearList"}, fileParameterNames...)
+}
@@ -17,6 +17,9 @@
 package core_test
 
 import (
+	"strings"
+	tpl "text/template"
+
 	"github.com/fbiville/headache/internal/pkg/core"
 	styles "github.com/fbiville/headache/internal/pkg/core/comment_styles"
 	. "github.com/onsi/ginkgo"
@@ -72,6 +75,42 @@ var _ = Describe("Template parser", func() {
 		Expect(result.ActualContent).To(Equal("# Copyright (c) {{.YearRange}} Florent"))
 	})
 
+	It("preserves the year list parameter for later substitution and detects year lists", func() {
+		yearListTemplate := core.HeaderTemplate{
+			Lines: []string{"Copyright (c) {{.YearList}} {{.Author}}"},
+			Data:  map[string]string{"Author": "Florent"},
+		}
+		versionedTemplate := &core.VersionedHeaderTemplate{
+			Previous: &yearListTemplate,
+			Current:  &yearListTemplate,
+			Revision: "",
+		}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.Hash{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ActualContent).To(Equal("# Copyright (c) {{.YearList}} Florent"))
+		Expect(result.DetectionRegex.MatchString("# Copyright (c) 2018, 2020-2022, 2024 Florent")).To(BeTrue())
+	})
+
+	It("preserves the file parameters for later substitution", func() {
+		fileTemplate := core.HeaderTemplate{
+			Lines: []string{"{{.FileName}} is part of {{.Module}}"},
+			Data:  map[string]string{},
+		}
+		versionedTemplate := &core.VersionedHeaderTemplate{
+			Previous: &fileTemplate,
+			Current:  &fileTemplate,
+			Revision: "",
+		}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.Hash{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ActualContent).To(Equal("# {{.FileName}} is part of {{.Module}}"))
+		Expect(result.DetectionRegex.MatchString("# main.go is part of github.com/acme/thing")).To(BeTrue())
+	})
+
 	It("replaces the legacy year range parameter with the newer parameters for later substitution", func() {
 		versionedTemplate := &core.VersionedHeaderTemplate{
 			Previous: &legacyTemplate,
@@ -100,4 +139,181 @@ var _ = Describe("Template parser", func() {
 		Expect(result.DetectionRegex.MatchString("\n// hello\n\n\n\n// world\n\n")).To(BeTrue(), "matches with extra newlines")
 		Expect(result.DetectionRegex.MatchString("\n// hello\n// \n// \n\n// world\n// \n\n")).To(BeTrue(), "matches with extra commented empty lines")
 	})
+
+	It("computes a regex that detects the current header as well", func() {
+		versionedTemplate := &core.VersionedHeaderTemplate{
+			Previous: &core.HeaderTemplate{Lines: []string{"hello"}, Data: map[string]string{}},
+			Current:  &core.HeaderTemplate{Lines: []string{"world"}, Data: map[string]string{}},
+			Revision: "",
+		}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.SlashSlash{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.DetectionRegex.MatchString("// world")).To(BeFalse(), "previous regex does not match current header")
+		Expect(result.CurrentDetectionRegex.MatchString("// world")).To(BeTrue(), "current regex matches current header")
+	})
+
+	It("computes a regex that detects legacy headers as well", func() {
+		versionedTemplate := &core.VersionedHeaderTemplate{
+			Previous: &core.HeaderTemplate{Lines: []string{"hello"}, Data: map[string]string{}},
+			Current:  &core.HeaderTemplate{Lines: []string{"hello"}, Data: map[string]string{}},
+			Legacy: []*core.HeaderTemplate{
+				{Lines: []string{"Copyright {{.Year}} {{.Author}}"}, Data: map[string]string{"Author": "Florent"}},
+				{Lines: []string{"bonjour"}, Data: map[string]string{}},
+			},
+			Revision: "",
+		}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.SlashSlash{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.DetectionRegex.MatchString("// hello")).To(BeTrue(), "matches previous header")
+		Expect(result.DetectionRegex.MatchString("// Copyright 2012 Someone")).To(BeTrue(), "matches first legacy header")
+		Expect(result.DetectionRegex.MatchString("# bonjour")).To(BeTrue(), "matches second legacy header")
+		Expect(result.DetectionRegex.MatchString("// world")).To(BeFalse(), "does not match anything else")
+	})
+
+	It("computes a regex that only detects headers holding the configured data", func() {
+		versionedTemplate := &core.VersionedHeaderTemplate{
+			Previous: &core.HeaderTemplate{Lines: []string{"Copyright {{.YearRange}} {{.Owner}}"}, Data: map[string]string{"Owner": "ACME"}},
+			Current:  &core.HeaderTemplate{Lines: []string{"Copyright {{.YearRange}} {{.Owner}}"}, Data: map[string]string{"Owner": "ACME Corp."}},
+			Revision: "",
+		}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.SlashSlash{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.DataDetectionRegex.MatchString("// Copyright 2018-2020 ACME Corp.")).To(BeTrue(), "matches current data")
+		Expect(result.DataDetectionRegex.MatchString("// Copyright 2018-2020 ACME")).To(BeTrue(), "matches previous data")
+		Expect(result.DetectionRegex.MatchString("// Copyright 2018-2020 Someone Else")).To(BeTrue(), "detection regex matches any data")
+		Expect(result.DataDetectionRegex.MatchString("// Copyright 2018-2020 Someone Else")).To(BeFalse(), "does not match other data")
+	})
+
+	It("applies template functions in the first pass", func() {
+		functionTemplate := core.HeaderTemplate{
+			Lines: []string{"Copyright {{.YearRange}} {{upper .Owner}}", "{{.Team | default \"Core\" | replace \"Core\" \"Kernel\"}} team"},
+			Data:  map[string]string{"Owner": "acme", "Team": ""},
+		}
+		versionedTemplate := &core.VersionedHeaderTemplate{Previous: &functionTemplate, Current: &functionTemplate}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.Hash{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ActualContent).To(Equal("# Copyright {{.YearRange}} ACME\n# Kernel team"))
+		Expect(result.DetectionRegex.MatchString("# Copyright 2020 ACME\n# Kernel team")).To(BeTrue())
+	})
+
+	It("defers functions called with reserved parameters to the second pass", func() {
+		functionTemplate := core.HeaderTemplate{
+			Lines: []string{"{{lower .Owner}} - {{upper .FileName}}"},
+			Data:  map[string]string{"Owner": "ACME"},
+		}
+		versionedTemplate := &core.VersionedHeaderTemplate{Previous: &functionTemplate, Current: &functionTemplate}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.Hash{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ActualContent).To(Equal("# acme - {{upper .FileName}}"))
+		Expect(result.DetectionRegex.MatchString("# acme - MAIN.GO")).To(BeTrue())
+	})
+
+	It("computes a regex that detects headers wrapped at the maximum line length of a comment style", func() {
+		wrappedTemplate := core.HeaderTemplate{
+			Lines:               []string{"Copyright {{.YearRange}} ACME Corporation and its many contributors"},
+			Data:                map[string]string{},
+			StyleMaxLineLengths: map[string]int{"hash": 30},
+		}
+		versionedTemplate := &core.VersionedHeaderTemplate{
+			Previous: &wrappedTemplate,
+			Current:  &wrappedTemplate,
+			Revision: "",
+		}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.Hash{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.DetectionRegex.MatchString("# Copyright 2020 ACME\n# Corporation and its many\n# contributors")).To(BeTrue())
+	})
+
+	It("comments every line of wrapped values", func() {
+		functionTemplate := core.HeaderTemplate{
+			Lines: []string{"{{wrap 20 .Notice}}"},
+			Data:  map[string]string{"Notice": "This file is part of a rather long notice"},
+		}
+		versionedTemplate := &core.VersionedHeaderTemplate{Previous: &functionTemplate, Current: &functionTemplate}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.Hash{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ActualContent).To(Equal("# This file is part of\n# a rather long notice"))
+		Expect(result.DetectionRegex.MatchString(result.ActualContent)).To(BeTrue())
+	})
+
+	It("escapes comment delimiters clashing with the template actions delimiters", func() {
+		versionedTemplate := &core.VersionedHeaderTemplate{Previous: &yearRangeTemplate, Current: &yearRangeTemplate}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.Handlebars{})
+
+		Expect(err).NotTo(HaveOccurred())
+		secondPass, err := tpl.New("second-pass").Parse(result.ActualContent)
+		Expect(err).NotTo(HaveOccurred())
+		builder := &strings.Builder{}
+		Expect(secondPass.Execute(builder, map[string]string{"YearRange": "2026"})).To(Succeed())
+		Expect(builder.String()).To(Equal("{{!--\nCopyright (c) 2026 Florent\n--}}"))
+		Expect(result.DetectionRegex.MatchString(builder.String() + "\n<p>{{title}}</p>")).To(BeTrue())
+	})
+
+	It("computes a regex that detects headers commented with another style", func() {
+		versionedTemplate := &core.VersionedHeaderTemplate{Previous: &yearRangeTemplate, Current: &yearRangeTemplate}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.Docstring{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ActualContent).To(Equal(`"""` + "\nCopyright (c) {{.YearRange}} Florent\n" + `"""`))
+		Expect(result.DetectionRegex.FindString("# Copyright (c) 2026 Florent\nimport os")).
+			To(Equal("# Copyright (c) 2026 Florent\n"))
+		Expect(result.DetectionRegex.FindString(`"""` + "\nCopyright (c) 2026 Florent\n" + `"""` + "\nimport os")).
+			To(Equal(`"""` + "\nCopyright (c) 2026 Florent\n" + `"""`))
+	})
+
+	It("defers conditional sections depending on file attributes to the second pass", func() {
+		conditionalTemplate := core.HeaderTemplate{
+			Lines: []string{
+				"Copyright {{.YearRange}} {{.Owner}}",
+				"{{if matches \"third_party/**\"}}",
+				"Portions copyright {{.Vendor}}",
+				"{{end}}",
+				"Licensed under MIT{{if eq .Extension \"go\"}}, see LICENSE{{end}}",
+			},
+			Data: map[string]string{"Owner": "ACME", "Vendor": "Someone"},
+		}
+		versionedTemplate := &core.VersionedHeaderTemplate{Previous: &conditionalTemplate, Current: &conditionalTemplate}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.Hash{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ActualContent).To(Equal("# Copyright {{.YearRange}} ACME\n" +
+			"{{- if matches \"third_party/**\"}}\n" +
+			"# Portions copyright Someone\n" +
+			"{{- end}}\n" +
+			"# Licensed under MIT{{if eq .Extension \"go\"}}, see LICENSE{{end}}"))
+		Expect(result.DetectionRegex.MatchString("# Copyright 2020 ACME\n# Portions copyright Someone\n# Licensed under MIT, see LICENSE")).
+			To(BeTrue(), "matches the header with the conditional sections")
+		Expect(result.DetectionRegex.MatchString("# Copyright 2020 ACME\n# Licensed under MIT")).
+			To(BeTrue(), "matches the header without the conditional sections")
+	})
+
+	It("executes conditional sections depending on data in the first pass", func() {
+		conditionalTemplate := core.HeaderTemplate{
+			Lines: []string{"Copyright {{.YearRange}} ACME", "{{if .Team}}", "Maintained by {{.Team}}", "{{end}}", "All rights reserved"},
+			Data:  map[string]string{"Team": ""},
+		}
+		versionedTemplate := &core.VersionedHeaderTemplate{Previous: &conditionalTemplate, Current: &conditionalTemplate}
+
+		result, err := core.ParseTemplate(versionedTemplate, styles.Hash{})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.ActualContent).To(Equal("# Copyright {{.YearRange}} ACME\n# All rights reserved"))
+	})
 })
@@ -16,16 +16,29 @@
 
 package core
 
-import "github.com/fbiville/headache/internal/pkg/helper"
+import (
+	"reflect"
+
+	"github.com/fbiville/headache/internal/pkg/helper"
+)
 
 type VersionedHeaderTemplate struct {
 	Current  *HeaderTemplate
 	Previous *HeaderTemplate
+	// older header templates, detected and replaced like the previous one
+	Legacy   []*HeaderTemplate
 	Revision string
 }
 
 func (t VersionedHeaderTemplate) RequiresFullScan() bool {
 	return t.Revision == "" ||
+		t.Current.License != t.Previous.License ||
+		!helper.SliceEqual(t.Current.LegacyHeaderFiles, t.Previous.LegacyHeaderFiles) ||
+		!reflect.DeepEqual(t.Current.Authors, t.Previous.Authors) ||
+		t.Current.MaxLineLength != t.Previous.MaxLineLength ||
+		!reflect.DeepEqual(t.Current.StyleMaxLineLengths, t.Previous.StyleMaxLineLengths) ||
+		!reflect.DeepEqual(t.Current.ExtensionMaxLineLengths, t.Previous.ExtensionMaxLineLengths) ||
+		!reflect.DeepEqual(t.Current.CustomStyles, t.Previous.CustomStyles) ||
 		!helper.SliceEqual(t.Current.Lines, t.Previous.Lines) ||
 		!helper.SliceEqual(helper.Keys(t.Current.Data), helper.Keys(t.Previous.Data))
 }
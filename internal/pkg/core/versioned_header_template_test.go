@@ -50,6 +50,66 @@ var _ = Describe("Versioned header template", func() {
 		Expect(template.RequiresFullScan()).To(BeTrue())
 	})
 
+	It("requires a full file scan if previous and current licenses do not match", func() {
+		current := template("same-contents", map[string]string{})
+		current.License = "MIT"
+		template := VersionedHeaderTemplate{
+			Revision: "some-sha",
+			Current:  current,
+			Previous: template("same-contents", map[string]string{}),
+		}
+
+		Expect(template.RequiresFullScan()).To(BeTrue())
+	})
+
+	It("requires a full file scan if previous and current legacy header files do not match", func() {
+		current := template("same-contents", map[string]string{})
+		current.LegacyHeaderFiles = []string{"old-header.txt"}
+		template := VersionedHeaderTemplate{
+			Revision: "some-sha",
+			Current:  current,
+			Previous: template("same-contents", map[string]string{}),
+		}
+
+		Expect(template.RequiresFullScan()).To(BeTrue())
+	})
+
+	It("requires a full file scan if previous and current author settings do not match", func() {
+		current := template("same-contents", map[string]string{})
+		current.Authors = &AuthorOptions{Limit: 3}
+		template := VersionedHeaderTemplate{
+			Revision: "some-sha",
+			Current:  current,
+			Previous: template("same-contents", map[string]string{}),
+		}
+
+		Expect(template.RequiresFullScan()).To(BeTrue())
+	})
+
+	It("requires a full file scan if previous and current maximum line lengths by comment style do not match", func() {
+		current := template("same-contents", map[string]string{})
+		current.StyleMaxLineLengths = map[string]int{"hash": 80}
+		template := VersionedHeaderTemplate{
+			Revision: "some-sha",
+			Current:  current,
+			Previous: template("same-contents", map[string]string{}),
+		}
+
+		Expect(template.RequiresFullScan()).To(BeTrue())
+	})
+
+	It("requires a full file scan if previous and current maximum line lengths do not match", func() {
+		current := template("same-contents", map[string]string{})
+		current.MaxLineLength = 100
+		template := VersionedHeaderTemplate{
+			Revision: "some-sha",
+			Current:  current,
+			Previous: template("same-contents", map[string]string{}),
+		}
+
+		Expect(template.RequiresFullScan()).To(BeTrue())
+	})
+
 	It("does not require a full file scan if revision is set and contents+data keys match", func() {
 		template := VersionedHeaderTemplate{
 			Revision: "some-sha",
@@ -47,6 +47,8 @@ func (fi *FileSystem) IsFile(path string) bool {
 type FileWriter interface {
 	Open(path string, mask int, permissions os.FileMode) (File, error)
 	Write(path string, contents string, permissions os.FileMode) error
+	Remove(path string) error
+	MkdirAll(path string, permissions os.FileMode) error
 }
 
 type OsFileWriter struct{}
@@ -62,6 +64,14 @@ func (*OsFileWriter) Write(path string, contents string, permissions os.FileMode
 	return ioutil.WriteFile(path, []byte(contents), permissions)
 }
 
+func (*OsFileWriter) Remove(path string) error {
+	return os.Remove(path)
+}
+
+func (*OsFileWriter) MkdirAll(path string, permissions os.FileMode) error {
+	return os.MkdirAll(path, permissions)
+}
+
 type File interface {
 	Write([]byte) error
 	Close() error
@@ -105,7 +115,6 @@ func UnsafeClose(file File) {
 	}
 }
 
-
 // test utility
 type FakeFileInfo struct {
 	FileMode os.FileMode
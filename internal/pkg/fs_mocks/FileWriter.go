@@ -11,6 +11,20 @@ type FileWriter struct {
 	mock.Mock
 }
 
+// MkdirAll provides a mock function with given fields: path, permissions
+func (_m *FileWriter) MkdirAll(path string, permissions os.FileMode) error {
+	ret := _m.Called(path, permissions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, os.FileMode) error); ok {
+		r0 = rf(path, permissions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Open provides a mock function with given fields: path, mask, permissions
 func (_m *FileWriter) Open(path string, mask int, permissions os.FileMode) (fs.File, error) {
 	ret := _m.Called(path, mask, permissions)
@@ -34,6 +48,20 @@ func (_m *FileWriter) Open(path string, mask int, permissions os.FileMode) (fs.F
 	return r0, r1
 }
 
+// Remove provides a mock function with given fields: path
+func (_m *FileWriter) Remove(path string) error {
+	ret := _m.Called(path)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Write provides a mock function with given fields: path, contents, permissions
 func (_m *FileWriter) Write(path string, contents string, permissions os.FileMode) error {
 	ret := _m.Called(path, contents, permissions)
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package license
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// parentheses, operators, license and exception identifiers
+	expressionTokenRegex = regexp.MustCompile(`\(|\)|[^\s()]+`)
+	// license identifiers such as MIT or GPL-2.0+, and references to other licenses such as LicenseRef-ACME
+	licenseIdRegex   = regexp.MustCompile(`^(?:(?:DocumentRef-[A-Za-z0-9.-]+:)?LicenseRef-[A-Za-z0-9.-]+|[A-Za-z0-9.-]+\+?)$`)
+	exceptionIdRegex = regexp.MustCompile(`^[A-Za-z0-9.-]+$`)
+)
+
+// checks the syntax of the SPDX license expression, e.g. "Apache-2.0 OR MIT", and returns it with the identifiers of
+// the supported licenses in their canonical form, the other identifiers being kept as is
+// see https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/
+func canonicalExpression(expression string) (string, error) {
+	parser := &expressionParser{tokens: expressionTokenRegex.FindAllString(expression, -1)}
+	err := parser.parseCompound()
+	if err == nil && parser.position < len(parser.tokens) {
+		err = fmt.Errorf("unexpected %q", parser.tokens[parser.position])
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid SPDX license expression %q\n\t%v", expression, err)
+	}
+	result := strings.Join(parser.tokens, " ")
+	return strings.Replace(strings.Replace(result, "( ", "(", -1), " )", ")", -1), nil
+}
+
+type expressionParser struct {
+	tokens   []string
+	position int
+}
+
+// parses license terms joined by the AND and OR operators
+func (parser *expressionParser) parseCompound() error {
+	if err := parser.parseTerm(); err != nil {
+		return err
+	}
+	for parser.peek() == "AND" || parser.peek() == "OR" {
+		parser.position++
+		if err := parser.parseTerm(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parses a parenthesized expression, or a license identifier followed by an optional exception
+func (parser *expressionParser) parseTerm() error {
+	token := parser.peek()
+	switch {
+	case token == "":
+		return fmt.Errorf("missing license identifier")
+	case token == "(":
+		parser.position++
+		if err := parser.parseCompound(); err != nil {
+			return err
+		}
+		if parser.peek() != ")" {
+			return fmt.Errorf("missing closing parenthesis")
+		}
+		parser.position++
+		return nil
+	case isOperator(token) || !licenseIdRegex.MatchString(token):
+		return fmt.Errorf("expected license identifier, got %q", token)
+	}
+	// the "+" suffix stands for the later versions of the license
+	id := strings.TrimSuffix(token, "+")
+	parser.tokens[parser.position] = CanonicalId(id) + strings.TrimPrefix(token, id)
+	parser.position++
+	if parser.peek() != "WITH" {
+		return nil
+	}
+	parser.position++
+	if exception := parser.peek(); isOperator(exception) || !exceptionIdRegex.MatchString(exception) {
+		return fmt.Errorf("expected license exception identifier, got %q", exception)
+	}
+	parser.position++
+	return nil
+}
+
+func (parser *expressionParser) peek() string {
+	if parser.position >= len(parser.tokens) {
+		return ""
+	}
+	return parser.tokens[parser.position]
+}
+
+func isOperator(token string) bool {
+	return token == "AND" || token == "OR" || token == "WITH"
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package license
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	copyrightLine     = "Copyright {{.YearRange}} {{.Owner}}"
+	spdxCopyrightLine = "SPDX-FileCopyrightText: {{.YearRange}} {{.Owner}}"
+)
+
+var headers = map[string]string{
+	"Apache-2.0": `Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.`,
+
+	"MIT": `Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.`,
+
+	"ISC": `Permission to use, copy, modify, and/or distribute this software for any
+purpose with or without fee is hereby granted, provided that the above
+copyright notice and this permission notice appear in all copies.
+
+THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.`,
+
+	"BSD-2-Clause": `Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+1. Redistributions of source code must retain the above copyright notice,
+   this list of conditions and the following disclaimer.
+
+2. Redistributions in binary form must reproduce the above copyright notice,
+   this list of conditions and the following disclaimer in the documentation
+   and/or other materials provided with the distribution.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.`,
+
+	"BSD-3-Clause": `Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+1. Redistributions of source code must retain the above copyright notice,
+   this list of conditions and the following disclaimer.
+
+2. Redistributions in binary form must reproduce the above copyright notice,
+   this list of conditions and the following disclaimer in the documentation
+   and/or other materials provided with the distribution.
+
+3. Neither the name of the copyright holder nor the names of its
+   contributors may be used to endorse or promote products derived from
+   this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.`,
+
+	"MPL-2.0": `This Source Code Form is subject to the terms of the Mozilla Public
+License, v. 2.0. If a copy of the MPL was not distributed with this
+file, You can obtain one at https://mozilla.org/MPL/2.0/.`,
+
+	"EPL-2.0": `This program and the accompanying materials are made available under the
+terms of the Eclipse Public License 2.0 which is available at
+https://www.eclipse.org/legal/epl-2.0/`,
+
+	"GPL-2.0-only":      gnuNotice("General Public License", "version 2 of the License."),
+	"GPL-2.0-or-later":  gnuNotice("General Public License", "either version 2 of the License, or\n(at your option) any later version."),
+	"GPL-3.0-only":      gnuNotice("General Public License", "version 3 of the License."),
+	"GPL-3.0-or-later":  gnuNotice("General Public License", "either version 3 of the License, or\n(at your option) any later version."),
+	"LGPL-2.1-or-later": gnuNotice("Lesser General Public License", "either version 2.1 of the License, or\n(at your option) any later version."),
+	"LGPL-3.0-or-later": gnuNotice("Lesser General Public License", "either version 3 of the License, or\n(at your option) any later version."),
+	"AGPL-3.0-or-later": gnuNotice("Affero General Public License", "either version 3 of the License, or\n(at your option) any later version."),
+}
+
+func gnuNotice(licenseName string, version string) string {
+	return fmt.Sprintf(`This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU %[1]s as published by
+the Free Software Foundation, %[2]s
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU %[1]s for more details.
+
+You should have received a copy of the GNU %[1]s
+along with this program. If not, see <https://www.gnu.org/licenses/>.`, licenseName, version)
+}
+
+// Returns the header template of the license with the given SPDX identifier
+// The template is parameterized with {{.YearRange}} and {{.Owner}}
+func Header(spdxId string) (string, error) {
+	for id, notice := range headers {
+		if strings.EqualFold(id, spdxId) {
+			return fmt.Sprintf("%s\n\n%s", copyrightLine, notice), nil
+		}
+	}
+	return "", unsupportedLicense(spdxId)
+}
+
+// Returns the short-form SPDX header template of the given SPDX license expression, such as MIT, LicenseRef-ACME or
+// Apache-2.0 OR MIT, the license does not need to be supported
+// The template is parameterized with {{.YearRange}} and {{.Owner}}
+func SpdxHeader(expression string) (string, error) {
+	canonicalForm, err := canonicalExpression(expression)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s\nSPDX-License-Identifier: %s", spdxCopyrightLine, canonicalForm), nil
+}
+
+// Returns the canonical form of the SPDX identifier if the license is supported, the identifier unchanged otherwise
+func CanonicalId(spdxId string) string {
+	for id := range headers {
+		if strings.EqualFold(id, spdxId) {
+			return id
+		}
+	}
+	return spdxId
+}
+
+// Returns the SPDX identifiers of the supported licenses in ascending order
+func SupportedIds() []string {
+	result := make([]string, 0, len(headers))
+	for id := range headers {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func unsupportedLicense(spdxId string) error {
+	return fmt.Errorf("unsupported license %q\n\tmust be one of: %s", spdxId, strings.Join(SupportedIds(), ","))
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package license_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/fbiville/headache/internal/pkg/license"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("License headers", func() {
+
+	It("are parameterized with the year range and owner", func() {
+		for _, id := range license.SupportedIds() {
+			header, err := license.Header(id)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(header).To(HavePrefix("Copyright {{.YearRange}} {{.Owner}}\n\n"), id)
+		}
+	})
+
+	It("are looked up case-insensitively", func() {
+		header, err := license.Header("mpl-2.0")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header).To(ContainSubstring("Mozilla Public"))
+	})
+
+	It("include the one of this project", func() {
+		projectHeader, err := ioutil.ReadFile("../../../license-header.txt")
+		Expect(err).NotTo(HaveOccurred())
+
+		header, err := license.Header("Apache-2.0")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.TrimSpace(header)).To(Equal(strings.TrimSpace(string(projectHeader))))
+	})
+
+	It("have a short SPDX form", func() {
+		header, err := license.SpdxHeader("apache-2.0")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header).To(Equal("SPDX-FileCopyrightText: {{.YearRange}} {{.Owner}}\nSPDX-License-Identifier: Apache-2.0"))
+	})
+
+	It("reject unknown licenses", func() {
+		_, err := license.Header("WTFPL")
+
+		Expect(err).To(MatchError(ContainSubstring(`unsupported license "WTFPL"`)))
+	})
+
+	It("have a short SPDX form for any license expression", func() {
+		for expression, identifier := range map[string]string{
+			"LicenseRef-ACME":   "LicenseRef-ACME",
+			"CC0-1.0":           "CC0-1.0",
+			"Unlicense":         "Unlicense",
+			"apache-2.0 OR mit": "Apache-2.0 OR MIT",
+			"(mit AND BSD-2-Clause) OR GPL-2.0+ WITH Classpath-exception-2.0": "(MIT AND BSD-2-Clause) OR GPL-2.0+ WITH Classpath-exception-2.0",
+			"DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2":                "DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2",
+		} {
+			header, err := license.SpdxHeader(expression)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(header).To(Equal("SPDX-FileCopyrightText: {{.YearRange}} {{.Owner}}\nSPDX-License-Identifier: " + identifier))
+		}
+	})
+
+	It("reject malformed SPDX license expressions", func() {
+		for _, expression := range []string{"", "MIT OR", "(MIT AND ISC", "MIT ISC", "MIT WITH", "Apache-2.0 or MIT", "MIT/ISC"} {
+			_, err := license.SpdxHeader(expression)
+
+			Expect(err).To(MatchError(ContainSubstring(`invalid SPDX license expression %q`, expression)))
+		}
+	})
+
+	It("are all referenced by the configuration schema", func() {
+		rawSchema, err := ioutil.ReadFile("../../../docs/schema.json")
+		Expect(err).NotTo(HaveOccurred())
+		// any SPDX expression is accepted in the spdx header mode, the built-in licenses are enumerated otherwise
+		schema := struct {
+			Else struct {
+				Properties struct {
+					License struct {
+						Enum []string `json:"enum"`
+					} `json:"license"`
+				} `json:"properties"`
+			} `json:"else"`
+		}{}
+		Expect(json.Unmarshal(rawSchema, &schema)).To(Succeed())
+
+		Expect(schema.Else.Properties.License.Enum).To(ConsistOf(license.SupportedIds()))
+	})
+})
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fbiville/headache/internal/pkg/core"
+)
+
+// Emits GitHub Actions workflow commands, annotating files requiring a header change or whose header changed
+// see https://docs.github.com/en/actions/reference/workflow-commands-for-github-actions
+type GithubReporter struct{}
+
+func (*GithubReporter) Report(writer io.Writer, reports []core.FileReport) error {
+	for _, report := range reports {
+		if !isReported(report) {
+			continue
+		}
+		command := "error"
+		if report.Status == core.ForeignHeader {
+			command = "warning"
+		} else if isChanged(report) {
+			command = "notice"
+		}
+		_, err := fmt.Fprintf(writer, "::%s file=%s,line=%d,endLine=%d::%s\n",
+			command,
+			escapeProperty(report.Path),
+			report.StartLine,
+			report.EndLine,
+			escapeData(describe(report)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func escapeData(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+}
+
+func escapeProperty(value string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(escapeData(value))
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/fbiville/headache/internal/pkg/core"
+)
+
+// Writes a JUnit XML test suite with one test case per file
+type JunitReporter struct{}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Type     string `xml:"type,attr"`
+	Contents string `xml:",chardata"`
+}
+
+func (*JunitReporter) Report(writer io.Writer, reports []core.FileReport) error {
+	suite := junitTestSuite{
+		Name:      "headache",
+		Tests:     len(reports),
+		TestCases: make([]junitTestCase, len(reports)),
+	}
+	for i, report := range reports {
+		testCase := junitTestCase{ClassName: "headache", Name: report.Path}
+		if report.RequiresChange() {
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message:  describe(report),
+				Type:     string(report.Status),
+				Contents: "Expected header:\n" + report.ExpectedHeader,
+			}
+		}
+		if report.Status == core.ForeignHeader {
+			suite.Skipped++
+			testCase.Skipped = &junitSkipped{Message: describe(report)}
+		}
+		suite.TestCases[i] = testCase
+	}
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
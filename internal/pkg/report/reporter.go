@@ -0,0 +1,100 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fbiville/headache/internal/pkg/core"
+)
+
+// Renders the file reports of a headache execution
+type Reporter interface {
+	Report(writer io.Writer, reports []core.FileReport) error
+}
+
+func SupportedReporters() map[string]Reporter {
+	return map[string]Reporter{
+		"text":   &TextReporter{},
+		"json":   &JsonReporter{},
+		"junit":  &JunitReporter{},
+		"github": &GithubReporter{},
+		"sarif":  &SarifReporter{},
+	}
+}
+
+func ParseReporter(format string) (Reporter, error) {
+	reporters := SupportedReporters()
+	if reporter, found := reporters[strings.ToLower(format)]; found {
+		return reporter, nil
+	}
+	return nil, fmt.Errorf("unexpected report format %q\n\tmust be one of: %s", format, strings.Join(SupportedFormats(), ","))
+}
+
+func SupportedFormats() []string {
+	reporters := SupportedReporters()
+	result := make([]string, 0, len(reporters))
+	for format := range reporters {
+		result = append(result, format)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func describe(report core.FileReport) string {
+	switch report.Status {
+	case core.MissingHeader:
+		return fmt.Sprintf("%s lacks the configured license header", report.Path)
+	case core.OutdatedHeader:
+		return fmt.Sprintf("%s has an outdated license header", report.Path)
+	case core.DuplicatedHeader:
+		return fmt.Sprintf("%s has several copies of the license header", report.Path)
+	case core.ForeignHeader:
+		return fmt.Sprintf("%s has a foreign copyright or license notice, left untouched", report.Path)
+	case core.OrphanedSidecar:
+		return fmt.Sprintf("%s is a license sidecar without source file", report.Path)
+	case core.DetectedHeader:
+		return fmt.Sprintf("%s has a license header to remove", report.Path)
+	case core.NoHeader:
+		return fmt.Sprintf("%s has no license header", report.Path)
+	case core.InsertedHeader:
+		return fmt.Sprintf("%s received the configured license header", report.Path)
+	case core.UpdatedHeader:
+		return fmt.Sprintf("%s had its license header updated", report.Path)
+	case core.RemovedSidecar:
+		return fmt.Sprintf("%s was a license sidecar without source file and has been removed", report.Path)
+	default:
+		return fmt.Sprintf("%s has an up-to-date license header", report.Path)
+	}
+}
+
+// foreign headers do not require any change but deserve the attention of the maintainers
+func isViolation(report core.FileReport) bool {
+	return report.RequiresChange() || report.Status == core.ForeignHeader
+}
+
+// files changed by a regular run are reported along with the violations
+func isReported(report core.FileReport) bool {
+	return isViolation(report) || isChanged(report)
+}
+
+func isChanged(report core.FileReport) bool {
+	return report.Status == core.InsertedHeader || report.Status == core.UpdatedHeader || report.Status == core.RemovedSidecar
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fbiville/headache/internal/pkg/core"
+	"github.com/fbiville/headache/internal/pkg/report"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reporters", func() {
+
+	var (
+		buffer  *bytes.Buffer
+		reports []core.FileReport
+	)
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+		reports = []core.FileReport{
+			{Path: "up-to-date.go", Status: core.UpToDateHeader, StartLine: 1, EndLine: 1, ExpectedHeader: "// Copyright 2019 ACME"},
+			{Path: "missing.go", Status: core.MissingHeader, StartLine: 1, EndLine: 1, ExpectedHeader: "// Copyright 2019 ACME"},
+			{Path: "some,dir/outdated.go", Status: core.OutdatedHeader, StartLine: 2, EndLine: 4, ExpectedHeader: "// Copyright 2018-2019 ACME"},
+			{Path: "vendored.go", Status: core.ForeignHeader, StartLine: 1, EndLine: 3, ExpectedHeader: "// Copyright 2019 ACME"},
+		}
+	})
+
+	It("are selected by format name", func() {
+		Expect(report.SupportedFormats()).To(Equal([]string{"github", "json", "junit", "sarif", "text"}))
+
+		reporter, err := report.ParseReporter("JUnit")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reporter).To(BeAssignableToTypeOf(&report.JunitReporter{}))
+	})
+
+	It("reject unknown formats", func() {
+		_, err := report.ParseReporter("csv")
+
+		Expect(err).To(MatchError(ContainSubstring(`unexpected report format "csv"`)))
+	})
+
+	It("lists violations and foreign headers as text", func() {
+		err := (&report.TextReporter{}).Report(buffer, reports)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buffer.String()).To(Equal("missing.go: missing-header\nsome,dir/outdated.go: outdated-header\nvendored.go: foreign-header\n"))
+	})
+
+	It("serializes all reports as JSON", func() {
+		err := (&report.JsonReporter{}).Report(buffer, reports)
+
+		Expect(err).NotTo(HaveOccurred())
+		var result []core.FileReport
+		Expect(json.Unmarshal(buffer.Bytes(), &result)).To(Succeed())
+		Expect(result).To(Equal(reports))
+	})
+
+	It("writes one JUnit test case per file", func() {
+		err := (&report.JunitReporter{}).Report(buffer, reports)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buffer.String()).To(Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="headache" tests="4" failures="2" skipped="1">
+    <testcase classname="headache" name="up-to-date.go"></testcase>
+    <testcase classname="headache" name="missing.go">
+      <failure message="missing.go lacks the configured license header" type="missing-header">Expected header:&#xA;// Copyright 2019 ACME</failure>
+    </testcase>
+    <testcase classname="headache" name="some,dir/outdated.go">
+      <failure message="some,dir/outdated.go has an outdated license header" type="outdated-header">Expected header:&#xA;// Copyright 2018-2019 ACME</failure>
+    </testcase>
+    <testcase classname="headache" name="vendored.go">
+      <skipped message="vendored.go has a foreign copyright or license notice, left untouched"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`))
+	})
+
+	It("annotates violations and foreign headers with GitHub Actions workflow commands", func() {
+		err := (&report.GithubReporter{}).Report(buffer, reports)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buffer.String()).To(Equal(
+			"::error file=missing.go,line=1,endLine=1::missing.go lacks the configured license header\n" +
+				"::error file=some%2Cdir/outdated.go,line=2,endLine=4::some,dir/outdated.go has an outdated license header\n" +
+				"::warning file=vendored.go,line=1,endLine=3::vendored.go has a foreign copyright or license notice, left untouched\n"))
+	})
+
+	It("lists the files changed by a regular run", func() {
+		updates := []core.FileReport{
+			{Path: "up-to-date.go", Status: core.UpToDateHeader, StartLine: 1, EndLine: 1},
+			{Path: "missing.go", Status: core.InsertedHeader, StartLine: 1, EndLine: 1},
+			{Path: "outdated.go", Status: core.UpdatedHeader, StartLine: 2, EndLine: 4},
+		}
+
+		Expect((&report.TextReporter{}).Report(buffer, updates)).To(Succeed())
+		Expect(buffer.String()).To(Equal("missing.go: inserted-header\noutdated.go: updated-header\n"))
+	})
+
+	It("annotates the files changed by a regular run with GitHub Actions notices", func() {
+		updates := []core.FileReport{{Path: "missing.go", Status: core.InsertedHeader, StartLine: 1, EndLine: 1}}
+
+		Expect((&report.GithubReporter{}).Report(buffer, updates)).To(Succeed())
+		Expect(buffer.String()).To(Equal("::notice file=missing.go,line=1,endLine=1::missing.go received the configured license header\n"))
+	})
+})
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/fbiville/headache/internal/pkg/core"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	Fixes     []sarifFix      `json:"fixes"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion  `json:"deletedRegion"`
+	InsertedContent sarifMessage `json:"insertedContent"`
+}
+
+var sarifRules = []sarifRule{
+	{Id: string(core.MissingHeader), ShortDescription: sarifMessage{Text: "File lacks the configured license header"}},
+	{Id: string(core.OutdatedHeader), ShortDescription: sarifMessage{Text: "File license header is outdated"}},
+	{Id: string(core.DuplicatedHeader), ShortDescription: sarifMessage{Text: "File license header is repeated"}},
+	{Id: string(core.ForeignHeader), ShortDescription: sarifMessage{Text: "File has a foreign copyright or license notice"}},
+	{Id: string(core.OrphanedSidecar), ShortDescription: sarifMessage{Text: "License sidecar file has no source file"}},
+	{Id: string(core.DetectedHeader), ShortDescription: sarifMessage{Text: "File license header is to be removed"}},
+}
+
+// Writes a SARIF 2.1.0 log with one result per file requiring a header change
+type SarifReporter struct{}
+
+func (*SarifReporter) Report(writer io.Writer, reports []core.FileReport) error {
+	results := make([]sarifResult, 0)
+	for _, report := range reports {
+		// changes already written are not results
+		if !isViolation(report) {
+			continue
+		}
+		results = append(results, sarifResultOf(report))
+	}
+	result := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "headache",
+				InformationUri: "https://github.com/fbiville/headache",
+				Rules:          sarifRules,
+			}},
+			Results: results,
+		}},
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+func sarifResultOf(report core.FileReport) sarifResult {
+	artifact := sarifArtifactLocation{Uri: report.Path}
+	region := sarifRegion{StartLine: report.StartLine, EndLine: report.EndLine}
+	replacement := sarifReplacement{
+		DeletedRegion:   region,
+		InsertedContent: sarifMessage{Text: report.WithLineEnding(report.ExpectedHeader)},
+	}
+	fixDescription := "Replace the license header"
+	switch report.Status {
+	case core.DetectedHeader:
+		fixDescription = "Remove the license header"
+	case core.MissingHeader:
+		fixDescription = "Insert the license header"
+		// insertion at the beginning of the line where the header is expected, below the preamble if any,
+		// nothing is deleted
+		replacement.DeletedRegion = sarifRegion{StartLine: report.StartLine, StartColumn: 1, EndLine: report.StartLine, EndColumn: 1}
+		replacement.InsertedContent.Text = report.WithLineEnding(report.ExpectedHeader + "\n\n")
+	}
+	result := sarifResult{
+		RuleId:  string(report.Status),
+		Level:   "error",
+		Message: sarifMessage{Text: describe(report)},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: artifact,
+			Region:           region,
+		}}},
+		Fixes: []sarifFix{{
+			Description: sarifMessage{Text: fixDescription},
+			ArtifactChanges: []sarifArtifactChange{{
+				ArtifactLocation: artifact,
+				Replacements:     []sarifReplacement{replacement},
+			}},
+		}},
+	}
+	switch report.Status {
+	case core.OrphanedSidecar:
+		// file deletions cannot be expressed as replacements
+		result.Fixes = []sarifFix{}
+	case core.ForeignHeader:
+		result.Level = "warning"
+		result.Fixes = []sarifFix{}
+	}
+	return result
+}
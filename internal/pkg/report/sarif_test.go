@@ -0,0 +1,151 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fbiville/headache/internal/pkg/core"
+	"github.com/fbiville/headache/internal/pkg/report"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SARIF report", func() {
+
+	var (
+		buffer   *bytes.Buffer
+		reports  []core.FileReport
+		reporter *report.SarifReporter
+	)
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+		reporter = &report.SarifReporter{}
+		reports = []core.FileReport{
+			{Path: "up-to-date.go", Status: core.UpToDateHeader, StartLine: 1, EndLine: 3, ExpectedHeader: "// Copyright 2019 ACME"},
+			{Path: "missing.go", Status: core.MissingHeader, StartLine: 1, EndLine: 1, ExpectedHeader: "// Copyright 2019 ACME"},
+			{Path: "outdated.go", Status: core.OutdatedHeader, StartLine: 2, EndLine: 4, ExpectedHeader: "// Copyright 2018-2019 ACME"},
+		}
+	})
+
+	It("includes one result per violation", func() {
+		err := reporter.Report(buffer, reports)
+
+		Expect(err).NotTo(HaveOccurred())
+		sarif := decode(buffer)
+		Expect(sarif["version"]).To(Equal("2.1.0"))
+		results := sarif["runs"].([]interface{})[0].(map[string]interface{})["results"].([]interface{})
+		Expect(results).To(HaveLen(2))
+		Expect(results[0]).To(HaveKeyWithValue("ruleId", "missing-header"))
+		Expect(results[1]).To(HaveKeyWithValue("ruleId", "outdated-header"))
+	})
+
+	It("does not include the files changed by a regular run", func() {
+		err := reporter.Report(buffer, []core.FileReport{{Path: "missing.go", Status: core.InsertedHeader, StartLine: 1, EndLine: 1}})
+
+		Expect(err).NotTo(HaveOccurred())
+		results := decode(buffer)["runs"].([]interface{})[0].(map[string]interface{})["results"].([]interface{})
+		Expect(results).To(BeEmpty())
+	})
+
+	It("locates missing headers at the first line and suggests inserting them", func() {
+		err := reporter.Report(buffer, reports[1:2])
+
+		Expect(err).NotTo(HaveOccurred())
+		result := firstResult(decode(buffer))
+		Expect(at(result, "locations", 0, "physicalLocation", "artifactLocation", "uri")).To(Equal("missing.go"))
+		Expect(at(result, "locations", 0, "physicalLocation", "region", "startLine")).To(BeNumerically("==", 1))
+		replacement := at(result, "fixes", 0, "artifactChanges", 0, "replacements", 0)
+		Expect(at(replacement, "deletedRegion", "endColumn")).To(BeNumerically("==", 1))
+		Expect(at(replacement, "insertedContent", "text")).To(Equal("// Copyright 2019 ACME\n\n"))
+	})
+
+	It("suggests inserting missing headers below the preamble, with the line ending of the file", func() {
+		err := reporter.Report(buffer, []core.FileReport{
+			{Path: "script.py", Status: core.MissingHeader, StartLine: 2, EndLine: 2, ExpectedHeader: "# Copyright 2019 ACME\n# All rights reserved", LineEnding: "\r\n"},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		replacement := at(firstResult(decode(buffer)), "fixes", 0, "artifactChanges", 0, "replacements", 0)
+		Expect(at(replacement, "deletedRegion", "startLine")).To(BeNumerically("==", 2))
+		Expect(at(replacement, "deletedRegion", "endLine")).To(BeNumerically("==", 2))
+		Expect(at(replacement, "insertedContent", "text")).To(Equal("# Copyright 2019 ACME\r\n# All rights reserved\r\n\r\n"))
+	})
+
+	It("locates outdated headers at their span and suggests replacing them", func() {
+		err := reporter.Report(buffer, reports[2:])
+
+		Expect(err).NotTo(HaveOccurred())
+		result := firstResult(decode(buffer))
+		Expect(at(result, "locations", 0, "physicalLocation", "region", "startLine")).To(BeNumerically("==", 2))
+		Expect(at(result, "locations", 0, "physicalLocation", "region", "endLine")).To(BeNumerically("==", 4))
+		replacement := at(result, "fixes", 0, "artifactChanges", 0, "replacements", 0)
+		Expect(at(replacement, "deletedRegion", "startLine")).To(BeNumerically("==", 2))
+		Expect(at(replacement, "deletedRegion", "endLine")).To(BeNumerically("==", 4))
+		Expect(at(replacement, "insertedContent", "text")).To(Equal("// Copyright 2018-2019 ACME"))
+	})
+
+	It("warns about foreign headers without suggesting any fix", func() {
+		err := reporter.Report(buffer, []core.FileReport{
+			{Path: "vendored.go", Status: core.ForeignHeader, StartLine: 1, EndLine: 3},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		result := firstResult(decode(buffer))
+		Expect(result).To(HaveKeyWithValue("ruleId", "foreign-header"))
+		Expect(result).To(HaveKeyWithValue("level", "warning"))
+		Expect(at(result, "locations", 0, "physicalLocation", "region", "endLine")).To(BeNumerically("==", 3))
+		Expect(at(result, "fixes")).To(BeEmpty())
+	})
+
+	It("reports orphaned sidecars without suggesting any fix", func() {
+		err := reporter.Report(buffer, []core.FileReport{
+			{Path: "deleted.png.license", Status: core.OrphanedSidecar, StartLine: 1, EndLine: 1},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		result := firstResult(decode(buffer))
+		Expect(result).To(HaveKeyWithValue("ruleId", "orphaned-sidecar"))
+		Expect(at(result, "message", "text")).To(Equal("deleted.png.license is a license sidecar without source file"))
+		Expect(at(result, "fixes")).To(BeEmpty())
+	})
+})
+
+func decode(buffer *bytes.Buffer) map[string]interface{} {
+	result := make(map[string]interface{})
+	Expect(json.Unmarshal(buffer.Bytes(), &result)).To(Succeed())
+	return result
+}
+
+func firstResult(sarif map[string]interface{}) interface{} {
+	return at(sarif, "runs", 0, "results", 0)
+}
+
+// navigates decoded JSON through object keys and array indices
+func at(value interface{}, path ...interface{}) interface{} {
+	for _, segment := range path {
+		switch key := segment.(type) {
+		case string:
+			value = value.(map[string]interface{})[key]
+		case int:
+			value = value.([]interface{})[key]
+		}
+	}
+	return value
+}
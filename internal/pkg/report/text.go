@@ -0,0 +1,39 @@
+/*
+ * Copyright 2026 Florent Biville (@fbiville)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fbiville/headache/internal/pkg/core"
+)
+
+// Lists files requiring a header change, whose header changed or with a foreign header, one per line
+type TextReporter struct{}
+
+func (*TextReporter) Report(writer io.Writer, reports []core.FileReport) error {
+	for _, report := range reports {
+		if !isReported(report) {
+			continue
+		}
+		if _, err := fmt.Fprintf(writer, "%s: %s\n", report.Path, report.Status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
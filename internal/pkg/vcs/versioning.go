@@ -19,6 +19,7 @@ package vcs
 import (
 	"fmt"
 	. "github.com/fbiville/headache/internal/pkg/helper"
+	"sort"
 	"strconv"
 	. "strings"
 	"time"
@@ -38,11 +39,33 @@ type FileChange struct {
 	Path            string
 	CreationYear    int
 	LastEditionYear int
+	// distinct years of the commits of the file, in ascending order
+	Years []int
+	// authors of the commits of the file, in order of first commit
+	Authors []Author
 }
 
 type FileHistory struct {
 	CreationYear    int
 	LastEditionYear int
+	// distinct years of the commits of the file, in ascending order
+	Years []int
+	// authors of the commits of the file, in order of first commit
+	Authors []Author
+}
+
+type Author struct {
+	Name  string
+	Email string
+	// distinct years of the commits of the author, in ascending order
+	Years   []int
+	Commits int
+}
+
+type commit struct {
+	timestamp   int64
+	authorName  string
+	authorEmail string
 }
 
 const (
@@ -71,6 +94,8 @@ func (client *Client) AddMetadata(changes []FileChange, clock Clock) ([]FileChan
 		}
 		change.CreationYear = history.CreationYear
 		change.LastEditionYear = history.LastEditionYear
+		change.Years = history.Years
+		change.Authors = history.Authors
 		changes[i] = change
 	}
 	return changes, nil
@@ -136,11 +161,12 @@ func GetUncommittedChanges(vcs Vcs) ([]FileChange, error) {
 }
 
 func GetFileHistory(vcs Vcs, file string, clock Clock) (*FileHistory, error) {
-	output, err := vcs.Log("--follow", "--name-status", "--format=%at", "--", file)
+	// author names and emails are mapped according to .mailmap
+	output, err := vcs.Log("--follow", "--name-status", "--format=%at%x09%aN%x09%aE", "--", file)
 	if err != nil {
 		return nil, err
 	}
-	timestamps, err := getCommitTimestamps(file, output)
+	commits, err := getCommits(file, output)
 	if err != nil {
 		return nil, err
 	}
@@ -148,20 +174,71 @@ func GetFileHistory(vcs Vcs, file string, clock Clock) (*FileHistory, error) {
 	history := FileHistory{
 		CreationYear:    defaultYear,
 		LastEditionYear: defaultYear,
+		Years:           []int{defaultYear},
 	}
 
-	if len(timestamps) > 0 {
-		minTimestamp := timestamps[len(timestamps)-1]
-		maxTimestamp := timestamps[0]
+	if len(commits) > 0 {
+		minTimestamp := commits[len(commits)-1].timestamp
+		maxTimestamp := commits[0].timestamp
 		history.CreationYear = time.Unix(minTimestamp, 0).Year()
 		history.LastEditionYear = time.Unix(maxTimestamp, 0).Year()
+		history.Years = distinctYears(commits)
+		history.Authors = authors(commits)
 	}
 
 	return &history, nil
 }
 
-func getCommitTimestamps(file string, log string) ([]int64, error) {
-	var result []int64
+func distinctYears(commits []commit) []int {
+	years := make(map[int]struct{}, len(commits))
+	for _, commit := range commits {
+		years[time.Unix(commit.timestamp, 0).Year()] = struct{}{}
+	}
+	result := make([]int, 0, len(years))
+	for year := range years {
+		result = append(result, year)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// groups commits by author email, authors are sorted by their first commit
+func authors(commits []commit) []Author {
+	result := make([]Author, 0)
+	indices := make(map[string]int)
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		if commit.authorName == "" && commit.authorEmail == "" {
+			continue
+		}
+		key := ToLower(commit.authorEmail)
+		index, found := indices[key]
+		if !found {
+			index = len(result)
+			indices[key] = index
+			result = append(result, Author{Name: commit.authorName, Email: commit.authorEmail})
+		}
+		author := &result[index]
+		author.Commits++
+		if year := time.Unix(commit.timestamp, 0).Year(); !containsYear(author.Years, year) {
+			author.Years = append(author.Years, year)
+			sort.Ints(author.Years)
+		}
+	}
+	return result
+}
+
+func containsYear(years []int, year int) bool {
+	for _, candidate := range years {
+		if candidate == year {
+			return true
+		}
+	}
+	return false
+}
+
+func getCommits(file string, log string) ([]commit, error) {
+	var result []commit
 	lines := Split(Replace(log, "\n\n", "\n", -1), "\n")
 	lines = lines[0 : len(lines)-1]
 	for i := 1; i < len(lines); i += 2 {
@@ -170,35 +247,41 @@ func getCommitTimestamps(file string, log string) ([]int64, error) {
 		if nameStatus == duplicatedRenamedContents || nameStatus == duplicatedCopiedContents {
 			continue
 		}
-		timestamp, err := strconv.ParseInt(lines[i-1], 10, 64)
+		fields := SplitN(lines[i-1], "\t", 3)
+		timestamp, err := strconv.ParseInt(fields[0], 10, 64)
 		if err != nil {
 			errorMsg := "could not parse timestamp (line %d) of file %q history. Full commit log below\n%s"
 			return nil, fmt.Errorf(errorMsg, i, file, log)
 		}
-		result = append(result, timestamp)
+		commit := commit{timestamp: timestamp}
+		if len(fields) == 3 {
+			commit.authorName = fields[1]
+			commit.authorEmail = fields[2]
+		}
+		result = append(result, commit)
 	}
 	return result, nil
 }
 
 func merge(changes []FileChange, changes2 []FileChange) []FileChange {
-	set := make(map[FileChange]struct{}, len(changes))
+	set := make(map[string]FileChange, len(changes))
 	for _, change := range changes {
-		set[change] = struct{}{}
+		set[change.Path] = change
 	}
 
 	for _, change := range changes2 {
-		if _, ok := set[change]; !ok {
-			set[change] = struct{}{}
+		if _, ok := set[change.Path]; !ok {
+			set[change.Path] = change
 		}
 	}
-	return keys(set)
+	return values(set)
 }
 
-func keys(set map[FileChange]struct{}) []FileChange {
+func values(set map[string]FileChange) []FileChange {
 	i := 0
 	result := make([]FileChange, len(set))
-	for key := range set {
-		result[i] = key
+	for _, value := range set {
+		result[i] = value
 		i++
 	}
 	return result
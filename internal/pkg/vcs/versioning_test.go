@@ -101,11 +101,11 @@ A	license-header.txt
 
 		var (
 			logArguments []interface{}
-			fakeTime FakeTime
+			fakeTime     FakeTime
 		)
 
 		BeforeEach(func() {
-			logArguments = []interface{}{"--follow", "--name-status", "--format=%at", "--"}
+			logArguments = []interface{}{"--follow", "--name-status", "--format=%at%x09%aN%x09%aE", "--"}
 			fakeTime = FakeTime{timestamp: fakeNow}
 		})
 
@@ -126,6 +126,49 @@ A	cmd/commands/ginkgo_suite_test.go
 			Expect(err).To(BeNil())
 			Expect(history.CreationYear).To(Equal(2017))
 			Expect(history.LastEditionYear).To(Equal(2018))
+			Expect(history.Years).To(Equal([]int{2017, 2018}))
+		})
+
+		It("retrieves the distinct commit years", func() {
+			vcsMock.On("Log", append(logArguments, "somefile.go")...).Return(`1656633600
+
+M	somefile.go
+1625097600
+
+M	somefile.go
+1593561600
+
+M	somefile.go
+1530403200
+
+A	somefile.go
+`, nil)
+
+			history, err := GetFileHistory(vcs, "somefile.go", FakeTime{})
+
+			Expect(err).To(BeNil())
+			Expect(history.Years).To(Equal([]int{2018, 2020, 2021, 2022}))
+		})
+
+		It("retrieves the authors of the file in order of first commit", func() {
+			vcsMock.On("Log", append(logArguments, "somefile.go")...).Return(`1656633600	Jane Doe	jane@acme.com
+
+M	somefile.go
+1625097600	John Smith	john@example.com
+
+M	somefile.go
+1593561600	Jane Doe	Jane@ACME.com
+
+A	somefile.go
+`, nil)
+
+			history, err := GetFileHistory(vcs, "somefile.go", FakeTime{})
+
+			Expect(err).To(BeNil())
+			Expect(history.Authors).To(Equal([]Author{
+				{Name: "Jane Doe", Email: "Jane@ACME.com", Years: []int{2020, 2022}, Commits: 2},
+				{Name: "John Smith", Email: "john@example.com", Years: []int{2021}, Commits: 1},
+			}))
 		})
 
 		It("returns current year for unversioned files", func() {
@@ -137,6 +180,7 @@ A	cmd/commands/ginkgo_suite_test.go
 			Expect(err).To(BeNil())
 			Expect(history.CreationYear).To(Equal(currentYear))
 			Expect(history.LastEditionYear).To(Equal(currentYear))
+			Expect(history.Years).To(Equal([]int{currentYear}))
 		})
 
 		It("returns the commit year for both creation and last edition year when file has been committed only once", func() {